@@ -0,0 +1,36 @@
+// 全量重建「买了又买」关联商品（按已支付订单统计共同购买次数，写入 Redis）
+//
+// 用法：go run ./cmd/build-related -config ./config
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/recommend"
+)
+
+func main() {
+	configPath := flag.String("config", "../../config", "配置文件目录")
+	flag.Parse()
+
+	// 1. 初始化配置
+	if err := config.InitConfig(*configPath); err != nil {
+		log.Fatalf("❌ 初始化配置失败: %v", err)
+	}
+
+	// 2. 初始化数据库和 Redis
+	dao.InitMySQL()
+	dao.InitRedis()
+
+	// 3. 重建关联商品
+	count, err := recommend.Rebuild(context.Background())
+	if err != nil {
+		log.Fatalf("❌ 重建关联推荐失败: %v", err)
+	}
+	fmt.Printf("✅ 关联推荐重建完成，写入 %d 个商品\n", count)
+}
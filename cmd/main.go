@@ -6,13 +6,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"xiaomi-mall/config"
 	"xiaomi-mall/internal/api/router"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/middleware"
 	"xiaomi-mall/internal/pkg/bloom"
+	"xiaomi-mall/internal/pkg/cache"
 	"xiaomi-mall/internal/pkg/consumer"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/internal/pkg/storage"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/internal/service/userService"
 	"xiaomi-mall/pkg/idgen"
 )
 
@@ -43,18 +49,66 @@ func main() {
 		log.Printf("⚠️  初始化秒杀布隆过滤器失败: %v", err)
 	}
 
-	// 4.6 初始化限流器
+	// 4.6 初始化本地缓存（二级缓存的一级），并监听跨实例失效广播
+	cache.InitLocal(config.AppConfig.Cache.LocalSize, time.Duration(config.AppConfig.Cache.LocalTTL)*time.Second)
+	cache.StartInvalidationListener()
+	fmt.Println("✅ 本地缓存初始化成功！")
+
+	// 4.7 初始化限流规则，并监听配置文件变更（限流规则热更新）
 	middleware.InitRateLimiters()
+	config.WatchConfig()
 	fmt.Println("✅ 限流器初始化成功！")
 
+	// 4.8 初始化文件存储（本地磁盘 / S3 兼容对象存储）
+	if err := storage.Init(config.AppConfig.OSS); err != nil {
+		log.Fatalf("❌ 初始化文件存储失败: %v", err)
+	}
+	fmt.Println("✅ 文件存储初始化成功！")
+
+	// 4.9 注册订单事件处理器（销量统计等）
+	userService.RegisterOrderEventHandlers()
+
+	// 4.10 注册低库存告警通知渠道
+	stockalert.Init(config.AppConfig.Stock)
+
 	// 5. 启动秒杀订单消费者（异步写入MySQL）
 	go consumer.ConsumeSeckillOrders()
 	fmt.Println("✅ 秒杀订单消费者已启动")
 
-	// 6. 启动订单超时扫描器（统一处理普通订单和秒杀订单）
+	// 6. 启动订单超时扫描器（统一处理普通订单和秒杀订单，启动时按 MySQL 重建延迟队列）
 	consumer.StartSeckillOrderTimeoutScanner()
 	fmt.Println("✅ 订单超时扫描器已启动")
 
+	// 6.1 启动库存镜像校正任务
+	consumer.StartStockSyncer()
+
+	// 6.2 启动点击量批量落库任务
+	consumer.StartViewFlusher()
+
+	// 6.3 启动运营位排期任务（过期下线、首页缓存失效）
+	consumer.StartCarouselScheduler()
+
+	// 6.4 启动价格规则排期任务（规则开始 / 结束时刷新售价）
+	consumer.StartPriceRuleScheduler()
+
+	// 6.5 启动定时上下架任务
+	consumer.StartSaleScheduler()
+
+	// 6.6 启动库存预占清扫任务（延迟队列丢失时兜底释放预占）
+	consumer.StartReservationSweeper()
+
+	// 6.7 标记上次服务停止时未完成的导入任务
+	adminService.Import.FailInterruptedImports()
+
+	// 6.8 启动订单补偿任务（延迟队列丢失时按 MySQL 关闭过期订单、自动确认收货）
+	consumer.StartOrderTimeoutCompensator()
+
+	// 6.9 启动待支付提醒队列（订单超时前 N 分钟发送站内通知）
+	consumer.StartPayReminderQueue()
+
+	// 6.10 启动自动确认收货队列（发货后 N 天未确认收货自动完成）
+	consumer.StartAutoConfirmQueue()
+
 	// 7. 初始化 Gin 框架
 	r := router.InitRouter()
 
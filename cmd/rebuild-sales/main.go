@@ -0,0 +1,33 @@
+// 按已支付订单重算商品销量（products.num），用于修复销量计数
+//
+// 用法：go run ./cmd/rebuild-sales -config ./config
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/dao"
+)
+
+func main() {
+	configPath := flag.String("config", "../../config", "配置文件目录")
+	flag.Parse()
+
+	// 1. 初始化配置
+	if err := config.InitConfig(*configPath); err != nil {
+		log.Fatalf("❌ 初始化配置失败: %v", err)
+	}
+
+	// 2. 初始化数据库
+	dao.InitMySQL()
+
+	// 3. 重算销量
+	rows, err := dao.Product.RebuildSalesNum()
+	if err != nil {
+		log.Fatalf("❌ 重算销量失败: %v", err)
+	}
+	fmt.Printf("✅ 销量重算完成，更新 %d 个商品\n", rows)
+}
@@ -2,17 +2,26 @@ package config
 
 import (
 	"fmt"
+	"log"
+	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 // Config 配置结构体
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
-	OSS      OSSConfig      `mapstructure:"oss"`
-	Jwt      JwtConfig      `mapstructure:"jwt"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	RabbitMQ  RabbitMQConfig  `mapstructure:"rabbitmq"`
+	OSS       OSSConfig       `mapstructure:"oss"`
+	Jwt       JwtConfig       `mapstructure:"jwt"`
+	Cache     CacheConfig     `mapstructure:"cache"`
+	Order     OrderConfig     `mapstructure:"order"`
+	Stock     StockConfig     `mapstructure:"stock"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Analytics AnalyticsConfig `mapstructure:"analytics"`
 }
 
 type ServerConfig struct {
@@ -31,10 +40,19 @@ type RabbitMQConfig struct {
 }
 
 type OSSConfig struct {
+	Driver    string `mapstructure:"driver"` // 存储后端：local（本地磁盘，由 Gin 提供静态访问）/ s3（S3 兼容对象存储，如 OSS、MinIO）
 	AccessKey string `mapstructure:"access_key"`
 	SecretKey string `mapstructure:"secret_key"`
 	Bucket    string `mapstructure:"bucket"`
-	Endpoint  string `mapstructure:"endpoint"`
+	Endpoint  string `mapstructure:"endpoint"`   // 如 https://oss-cn-hangzhou.aliyuncs.com
+	Region    string `mapstructure:"region"`     // 签名用的区域，如 oss-cn-hangzhou / us-east-1
+	PathStyle bool   `mapstructure:"path_style"` // 使用 endpoint/bucket/key 形式访问（MinIO 需要开启）
+	PublicURL string `mapstructure:"public_url"` // 对外访问地址（CDN 域名），为空时使用存储自身地址
+
+	LocalDir       string `mapstructure:"local_dir"`        // 本地存储目录
+	LocalURLPrefix string `mapstructure:"local_url_prefix"` // 本地存储的访问路径前缀
+
+	MaxSize int64 `mapstructure:"max_size"` // 单个文件大小上限（字节）
 }
 
 type JwtConfig struct {
@@ -42,14 +60,82 @@ type JwtConfig struct {
 	AccessExpire int64  `mapstructure:"access_expire"`
 }
 
+type CacheConfig struct {
+	LocalSize int `mapstructure:"local_size"` // 进程内 LRU 最大条目数，0 表示关闭一级缓存
+	LocalTTL  int `mapstructure:"local_ttl"`  // 进程内缓存有效期（秒）
+}
+
+type OrderConfig struct {
+	NormalTimeoutMinutes  int `mapstructure:"normal_timeout_minutes"`  // 普通订单支付超时时间（分钟）
+	SeckillTimeoutMinutes int `mapstructure:"seckill_timeout_minutes"` // 秒杀订单支付超时时间（分钟）
+	PayReminderMinutes    int `mapstructure:"pay_reminder_minutes"`    // 距离超时还剩多少分钟时提醒用户支付，0 表示不提醒
+	AutoConfirmDays       int `mapstructure:"auto_confirm_days"`       // 发货后多少天自动确认收货
+	ConfirmExtendDays     int `mapstructure:"confirm_extend_days"`     // 用户延长收货一次顺延的天数
+
+	ReserveRetries      int `mapstructure:"reserve_retries"`        // 预占库存遇到乐观锁版本冲突时的最大重试次数，0 表示不重试
+	ReserveBackoffMs    int `mapstructure:"reserve_backoff_ms"`     // 第一次重试前的等待时间（毫秒），之后指数增长并加随机抖动
+	ReserveMaxBackoffMs int `mapstructure:"reserve_max_backoff_ms"` // 单次重试等待时间上限（毫秒）
+
+	TimeoutWorkers         int `mapstructure:"timeout_workers"`           // 超时关单 worker 数
+	TimeoutBatchSize       int `mapstructure:"timeout_batch_size"`        // 每次从延迟队列认领的最大订单数
+	TimeoutLeaseSeconds    int `mapstructure:"timeout_lease_seconds"`     // 认领租约（秒），超时未确认的订单会被其他 worker 重新认领
+	TimeoutRetrySeconds    int `mapstructure:"timeout_retry_seconds"`     // 关单失败后第一次重试间隔（秒），之后每次翻倍
+	TimeoutMaxRetrySeconds int `mapstructure:"timeout_max_retry_seconds"` // 关单失败重试间隔上限（秒）
+
+	CompensateIntervalSeconds int `mapstructure:"compensate_interval_seconds"` // 超时关单补偿任务（扫描 MySQL）的执行间隔（秒）
+}
+
+// PayTimeout 订单支付超时时间（orderType：1 普通订单，2 秒杀订单）
+func (c OrderConfig) PayTimeout(orderType int) time.Duration {
+	if orderType == 2 {
+		return time.Duration(c.SeckillTimeoutMinutes) * time.Minute
+	}
+	return time.Duration(c.NormalTimeoutMinutes) * time.Minute
+}
+
+type StockConfig struct {
+	AlertNotifiers []string `mapstructure:"alert_notifiers"` // 低库存告警通知方式，可多选：log / webhook / email
+	WebhookURL     string   `mapstructure:"webhook_url"`     // 告警 Webhook 地址（POST JSON）
+	AlertEmails    []string `mapstructure:"alert_emails"`    // 告警邮件收件人（当前为占位实现，只打印日志）
+}
+
+type AnalyticsConfig struct {
+	Salt string `mapstructure:"salt"` // 匿名访客 ID 的 HMAC 密钥（独立于 JWT 密钥，修改后所有访客 ID 都会变化）
+}
+
+// RateLimitConfig 限流规则（支持热更新：修改配置文件后无需重启即生效）
+type RateLimitConfig struct {
+	Enabled bool            `mapstructure:"enabled"` // 总开关
+	Rules   []RateLimitRule `mapstructure:"rules"`   // 一个请求可命中多条规则，任意一条超限即拒绝（被拒绝的请求不消耗其他规则的配额）
+}
+
+// RateLimitRule 一条限流规则
+type RateLimitRule struct {
+	Name      string        `mapstructure:"name"`      // 规则名（用于 Redis key 和日志，需唯一）
+	Path      string        `mapstructure:"path"`      // 路由：与 Gin 路由一致（如 /api/products/:product_id），以 * 结尾时按请求路径前缀匹配
+	Method    string        `mapstructure:"method"`    // 请求方法，为空匹配所有方法
+	Dimension string        `mapstructure:"dimension"` // 限流维度：ip / user（未登录按 IP）/ api（按路由共享）/ global（命中规则的请求共享）
+	Algorithm string        `mapstructure:"algorithm"` // 限流算法：sliding_window / token_bucket / gcra
+	Limit     int           `mapstructure:"limit"`     // 每个窗口允许的请求数
+	Window    time.Duration `mapstructure:"window"`    // 时间窗口，如 1s、1m
+	Burst     int           `mapstructure:"burst"`     // 令牌桶 / GCRA 的突发容量，0 表示等于 limit
+}
+
 // 全局配置实例
 var AppConfig *Config
 
+// 配置变更监听
+var (
+	changeMu       sync.Mutex
+	changeHandlers []func(cfg *Config)
+)
+
 // InitConfig 初始化配置
 func InitConfig(configPath string) error {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(configPath)
+	setDefaults()
 
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
@@ -64,3 +150,70 @@ func InitConfig(configPath string) error {
 
 	return nil
 }
+
+// OnChange 注册配置变更回调（配置文件修改后收到重新解析的完整配置）
+// 注意：AppConfig 保持启动时的值，只有注册了回调的模块会热更新
+func OnChange(handler func(cfg *Config)) {
+	changeMu.Lock()
+	defer changeMu.Unlock()
+	changeHandlers = append(changeHandlers, handler)
+}
+
+// WatchConfig 监听配置文件变更，解析成功后通知 OnChange 注册的回调
+func WatchConfig() {
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		cfg := &Config{}
+		if err := viper.Unmarshal(cfg); err != nil {
+			log.Printf("❌ 配置文件 %s 已修改，但解析失败，继续使用旧配置: %v", e.Name, err)
+			return
+		}
+		log.Printf("🔄 配置文件已重新加载: %s", e.Name)
+
+		changeMu.Lock()
+		handlers := append([]func(cfg *Config){}, changeHandlers...)
+		changeMu.Unlock()
+		for _, handler := range handlers {
+			handler(cfg)
+		}
+	})
+	viper.WatchConfig()
+}
+
+// setDefaults 配置默认值（配置文件中没写的项使用这里的值）
+func setDefaults() {
+	viper.SetDefault("cache.local_size", 10000)
+	viper.SetDefault("cache.local_ttl", 30)
+
+	viper.SetDefault("oss.driver", "local")
+	viper.SetDefault("oss.local_dir", "./uploads")
+	viper.SetDefault("oss.local_url_prefix", "/uploads")
+	viper.SetDefault("oss.max_size", 5<<20) // 5MB
+
+	viper.SetDefault("order.normal_timeout_minutes", 30)
+	viper.SetDefault("order.seckill_timeout_minutes", 30)
+	viper.SetDefault("order.pay_reminder_minutes", 5)
+	viper.SetDefault("order.auto_confirm_days", 7)
+	viper.SetDefault("order.confirm_extend_days", 3)
+	viper.SetDefault("order.reserve_retries", 3)
+	viper.SetDefault("order.reserve_backoff_ms", 10)
+	viper.SetDefault("order.reserve_max_backoff_ms", 200)
+	viper.SetDefault("order.timeout_workers", 8)
+	viper.SetDefault("order.timeout_batch_size", 100)
+	viper.SetDefault("order.timeout_lease_seconds", 30)
+	viper.SetDefault("order.timeout_retry_seconds", 5)
+	viper.SetDefault("order.timeout_max_retry_seconds", 300)
+	viper.SetDefault("order.compensate_interval_seconds", 300)
+
+	viper.SetDefault("stock.alert_notifiers", []string{"log"})
+
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.rules", []map[string]interface{}{
+		// 全局：1 秒内 10000 次
+		{"name": "global", "path": "/api/*", "dimension": "global", "algorithm": "gcra", "limit": 10000, "window": "1s"},
+		// 商品 / 秒杀浏览：单 IP 1 秒内 100 次（防爬虫）
+		{"name": "product_ip", "path": "/api/products*", "method": "GET", "dimension": "ip", "algorithm": "sliding_window", "limit": 100, "window": "1s"},
+		{"name": "seckill_ip", "path": "/api/seckill*", "method": "GET", "dimension": "ip", "algorithm": "sliding_window", "limit": 100, "window": "1s"},
+		// 秒杀下单：单用户 1 秒内 1 次，不允许突发
+		{"name": "seckill_order", "path": "/api/seckill/order", "method": "POST", "dimension": "user", "algorithm": "gcra", "limit": 1, "window": "1s", "burst": 1},
+	})
+}
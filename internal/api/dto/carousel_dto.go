@@ -0,0 +1,36 @@
+package dto
+
+// ========== 管理端：创建运营位 ==========
+type CreateCarouselReq struct {
+	Slot           string `json:"slot" binding:"required,oneof=home_carousel category_top new_arrival"` // 运营位
+	SlotCategoryID uint   `json:"slot_category_id"`                                                     // 分类精选必填
+	Title          string `json:"title" binding:"omitempty,max=100"`
+	ImgPath        string `json:"img_path" binding:"required,max=255"`
+	TargetType     string `json:"target_type" binding:"required,oneof=product category url"` // 跳转类型
+	ProductID      uint   `json:"product_id"`                                                // target_type=product 时必填
+	CategoryID     uint   `json:"category_id"`                                               // target_type=category 时必填
+	URL            string `json:"url" binding:"omitempty,url,max=500"`                       // target_type=url 时必填
+	StartTime      string `json:"start_time" binding:"required"`                             // 格式："2026-01-23 10:00:00"
+	EndTime        string `json:"end_time" binding:"required"`                               // 格式："2026-01-30 10:00:00"
+	Sort           int    `json:"sort"`                                                      // 越小越靠前
+}
+
+// ========== 管理端：运营位 ID（路径参数）==========
+type CarouselIDReq struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// ========== 管理端：更新运营位（全量更新）==========
+type UpdateCarouselReq struct {
+	ID uint `json:"-"` // 来自路径参数
+	CreateCarouselReq
+	Status int8 `json:"status" binding:"oneof=0 1"` // 0:停用 1:启用
+}
+
+// ========== 管理端：运营位列表 ==========
+type CarouselListReq struct {
+	Slot     string `form:"slot" binding:"omitempty,oneof=home_carousel category_top new_arrival"`
+	Status   *int8  `form:"status" binding:"omitempty,oneof=0 1 2"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
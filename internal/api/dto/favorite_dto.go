@@ -0,0 +1,24 @@
+package dto
+
+// ========== 收藏 / 取消收藏 - POST|DELETE /favorites/:product_id ==========
+type FavoriteReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+}
+
+// ========== 收藏列表 ==========
+type FavoriteListReq struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=50"`
+}
+
+// ========== 通知列表 ==========
+type NotificationListReq struct {
+	Page       int  `form:"page" binding:"omitempty,min=1"`
+	PageSize   int  `form:"page_size" binding:"omitempty,min=1,max=50"`
+	UnreadOnly bool `form:"unread_only"` // 只看未读
+}
+
+// ========== 标记通知已读 ==========
+type ReadNotificationReq struct {
+	IDs []uint `json:"ids" binding:"omitempty,max=100"` // 为空表示全部已读
+}
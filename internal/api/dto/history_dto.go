@@ -0,0 +1,18 @@
+package dto
+
+// ========== 浏览历史列表 - GET /user/history ==========
+type HistoryListReq struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=50"`
+}
+
+// ========== 删除单条浏览历史 - DELETE /user/history/:product_id ==========
+type DeleteHistoryReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+}
+
+// ========== 管理端：浏览路径分析 - GET /admin/analytics/view_paths ==========
+type ViewPathsReq struct {
+	Cursor uint64 `form:"cursor"`                                  // SCAN 游标，首次传 0
+	Count  int64  `form:"count" binding:"omitempty,min=1,max=500"` // 每次扫描的 key 数量（近似值），默认 100
+}
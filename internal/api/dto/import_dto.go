@@ -0,0 +1,14 @@
+package dto
+
+// 导入任务 ID 路径参数 - /admin/product/import/:job_id
+type ImportJobIDReq struct {
+	JobID uint `uri:"job_id" binding:"required,min=1"`
+}
+
+// 导出商品请求 - GET /admin/product/export?format=xlsx&category_id=1
+type ExportProductsReq struct {
+	Format     string `form:"format" binding:"omitempty,oneof=csv xlsx"` // 默认 xlsx
+	CategoryID uint   `form:"category_id"`                               // 分类ID，可选
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"`       // 商品名关键词，可选
+	OnSale     *bool  `form:"on_sale"`                                   // 是否上架，可选
+}
@@ -0,0 +1,25 @@
+package dto
+
+// ========== 管理端：设置 SKU 低库存告警阈值 ==========
+type UpdateSkuLowStockThresholdReq struct {
+	ProductSKUID uint `json:"product_sku_id" binding:"required,min=1"`
+	Threshold    *int `json:"threshold" binding:"required,min=0"` // 可售库存 <= 阈值时告警，0 表示不告警
+}
+
+// ========== 管理端：低库存 SKU 列表 ==========
+type LowStockListReq struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== 管理端：低库存告警记录 ==========
+type StockAlertListReq struct {
+	Status   *int8 `form:"status" binding:"omitempty,oneof=0 1"` // 0:告警中 1:已恢复
+	Page     int   `form:"page" binding:"omitempty,min=1"`
+	PageSize int   `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== 用户端：到货提醒（路径参数）==========
+type StockSubscriptionReq struct {
+	SkuID uint `uri:"sku_id" binding:"required,min=1"`
+}
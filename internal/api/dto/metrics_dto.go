@@ -0,0 +1,6 @@
+package dto
+
+// ========== 管理端：业务计数器 ==========
+type MetricsReq struct {
+	Prefix string `form:"prefix"` // 可选，只返回该前缀的计数器，如 order_stock_
+}
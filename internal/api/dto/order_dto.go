@@ -41,9 +41,21 @@ type ConfirmOrderReq struct {
 	OrderNo string `json:"order_no" binding:"required"`
 }
 
+// ========== 延长收货 ==========
+type ExtendConfirmReq struct {
+	OrderNo string `json:"order_no" binding:"required"`
+}
+
 // ========== 管理端：发货 ==========
 type ShipOrderReq struct {
 	OrderNo        string `json:"order_no" binding:"required"`
 	TrackingNumber string `json:"tracking_number" binding:"required,max=50"` // 物流单号
 	AdminRemark    string `json:"admin_remark" binding:"omitempty,max=200"`  // 管理员备注
 }
+
+// ========== 管理端：退款 ==========
+type RefundOrderReq struct {
+	OrderNo     string `json:"order_no" binding:"required"`
+	AdminRemark string `json:"admin_remark" binding:"omitempty,max=200"` // 退款原因
+	Operator    string `json:"operator" binding:"omitempty,max=64"`      // 操作人（记录在库存流水中）
+}
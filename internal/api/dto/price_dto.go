@@ -0,0 +1,31 @@
+package dto
+
+// ========== 管理端：创建价格规则 ==========
+type CreatePriceRuleReq struct {
+	ProductSkuID uint   `json:"product_sku_id" binding:"required,min=1"`
+	Price        int64  `json:"price" binding:"required,min=1"`     // 规则价格，单位：分
+	StartTime    string `json:"start_time" binding:"required"`      // 格式："2026-01-23 10:00:00"
+	EndTime      string `json:"end_time" binding:"required"`        // 格式："2026-01-30 10:00:00"
+	Remark       string `json:"remark" binding:"omitempty,max=255"` // 备注，如活动名称
+}
+
+// ========== 管理端：价格规则 ID（路径参数）==========
+type PriceRuleIDReq struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// ========== 管理端：价格规则列表 ==========
+type PriceRuleListReq struct {
+	ProductSkuID uint  `form:"product_sku_id"`
+	Status       *int8 `form:"status" binding:"omitempty,oneof=0 1"`
+	Page         int   `form:"page" binding:"omitempty,min=1"`
+	PageSize     int   `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== 管理端：售价历史 ==========
+type PriceHistoryReq struct {
+	ProductSkuID uint   `form:"product_sku_id" binding:"required,min=1"`
+	At           string `form:"at"` // 可选，查询该时刻的售价，格式："2026-01-23 10:00:00"
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
@@ -1,10 +1,13 @@
 package dto
 
+import "xiaomi-mall/internal/pkg/types"
+
 type SKUItem struct {
-	Title string `json:"title" binding:"required"`
-	Price int64  `json:"price" binding:"required"`
-	Stock int    `json:"stock" binding:"required"`
-	Code  string `json:"code"`
+	Title  string   `json:"title" binding:"required"`
+	Price  int64    `json:"price" binding:"required"`
+	Stock  int      `json:"stock" binding:"required"`
+	Code   string   `json:"code"`
+	Images []string `json:"images" binding:"max=20,dive,required,max=500"` // SKU 图集（可选，第一张为 SKU 图）
 }
 
 // ============ 商品管理 DTO ============
@@ -19,13 +22,31 @@ type CreateProductReq struct {
 	Price         int64     `json:"price"`
 	DiscountPrice int64     `json:"discount_price"`
 	SKUs          []SKUItem `json:"skus" binding:"required,min=1"`
+
+	Images  []string             `json:"images" binding:"max=20,dive,required,max=500"` // 商品图集（可选，img_path 为空时第一张作为封面）
+	Content []types.ContentBlock `json:"content" binding:"max=200,dive"`                // 图文详情（可选）
 }
 
-// 更新商品库存请求
+// 调整商品库存请求（以增减量表示，必须填写原因）
 type UpdateProductStockReq struct {
-	ProductID    uint `json:"product_id"`
-	Stock        int  `json:"stock"`
-	ProductSKUID uint `json:"product_sku_id"`
+	ProductSKUID uint   `json:"product_sku_id" binding:"required,min=1"`
+	Delta        int    `json:"delta" binding:"required,ne=0"`       // 库存增减量（正数入库，负数出库）
+	Reason       string `json:"reason" binding:"required,max=255"`   // 调整原因，如盘点、损耗、采购入库
+	Operator     string `json:"operator" binding:"omitempty,max=64"` // 操作人
+}
+
+// 修改 SKU 下单预占方式请求
+type UpdateSkuStockModeReq struct {
+	ProductSKUID uint  `json:"product_sku_id" binding:"required,min=1"`
+	StockMode    *int8 `json:"stock_mode" binding:"required,oneof=0 1"` // 0:乐观锁 1:条件扣减（热点 SKU）
+}
+
+// SKU 库存流水请求
+type StockMovementListReq struct {
+	ProductSKUID uint   `json:"-"`
+	Reason       string `form:"reason"` // 可选，按变更原因筛选
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
 }
 
 // 更新商品上架状态请求
@@ -34,6 +55,55 @@ type UpdateProductOnSaleReq struct {
 	OnSale    bool `json:"on_sale"`
 }
 
+// 设置定时上下架请求（时间为空表示取消该项定时）
+type UpdateSaleScheduleReq struct {
+	ProductID uint   `json:"product_id" binding:"required,min=1"`
+	OnSaleAt  string `json:"on_sale_at"`  // 定时上架时间，格式："2026-01-23 10:00:00"
+	OffSaleAt string `json:"off_sale_at"` // 定时下架时间，格式："2026-01-30 10:00:00"
+}
+
+// 更新 SKU 价格请求
+type UpdateSkuPriceReq struct {
+	ProductSKUID uint  `json:"product_sku_id" binding:"required,min=1"`
+	Price        int64 `json:"price" binding:"required,min=1"` // 单位：分
+}
+
+// 更新商品展示价格 / 折扣价请求
+type UpdateProductPriceReq struct {
+	ProductID     uint  `json:"product_id" binding:"required,min=1"`
+	Price         int64 `json:"price" binding:"required,min=1"`           // 展示价格，单位：分
+	DiscountPrice int64 `json:"discount_price" binding:"omitempty,min=0"` // 折扣价，0 表示不打折
+}
+
+// 商品 ID 路径参数 - /admin/product/:product_id/...
+type ProductIDReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+}
+
+// SKU ID 路径参数 - /admin/product/sku/:sku_id/...
+type SkuIDReq struct {
+	ProductSKUID uint `uri:"sku_id" binding:"required,min=1"`
+}
+
+// 替换商品 / SKU 图集请求 - PUT /admin/product/:product_id/images
+type UpdateProductImagesReq struct {
+	ProductID    uint     `json:"-"`                                             // 从路径参数获取
+	ProductSkuID uint     `json:"product_sku_id"`                                // 0 表示商品主图集
+	Images       []string `json:"images" binding:"max=20,dive,required,max=500"` // 按展示顺序，第一张同步为封面；传空数组清空图集
+}
+
+// 保存商品图文详情请求 - PUT /admin/product/:product_id/content
+type UpdateProductContentReq struct {
+	ProductID uint                 `json:"-"` // 从路径参数获取
+	Content   []types.ContentBlock `json:"content" binding:"max=200,dive"`
+}
+
+// 商品浏览统计请求 - GET /admin/product/:product_id/views?days=7
+type ProductViewStatsReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+	Days      int  `form:"days" binding:"omitempty,min=1,max=90"` // 最近 N 天，默认 7 天
+}
+
 // ============ 商品查询 DTO ============
 
 type ProductListReq struct {
@@ -51,6 +121,12 @@ type ProductDetailReq struct {
 	ProductID uint `uri:"product_id" binding:"required,min=1"`
 }
 
+// 关联商品请求 - GET /products/:product_id/related?limit=10
+type RelatedProductsReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+	Limit     int  `form:"limit" binding:"omitempty,min=1,max=20"` // 默认 10
+}
+
 // SKU 详情请求 - GET /products/skus/:sku_id
 type SkuDetailReq struct {
 	SkuID uint `uri:"sku_id" binding:"required,min=1"`
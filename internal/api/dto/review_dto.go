@@ -0,0 +1,36 @@
+package dto
+
+// ========== 发表评价 ==========
+type CreateReviewReq struct {
+	OrderItemID uint     `json:"order_item_id" binding:"required,min=1"`    // 订单商品 ID
+	Star        int      `json:"star" binding:"required,min=1,max=5"`       // 1-5 星
+	Content     string   `json:"content" binding:"omitempty,max=500"`       // 评价内容
+	Images      []string `json:"images" binding:"omitempty,max=9,dive,url"` // 图片 URL，最多 9 张
+}
+
+// ========== 追评 ==========
+type FollowUpReviewReq struct {
+	ReviewID uint     `json:"review_id" binding:"required,min=1"`
+	Content  string   `json:"content" binding:"required,max=500"`
+	Images   []string `json:"images" binding:"omitempty,max=9,dive,url"`
+}
+
+// ========== 商品评价列表 - GET /products/:product_id/reviews ==========
+type ProductReviewListReq struct {
+	ProductID uint `uri:"product_id" binding:"required,min=1"`
+	Page      int  `form:"page" binding:"omitempty,min=1"`
+	PageSize  int  `form:"page_size" binding:"omitempty,min=1,max=50"`
+	Star      int  `form:"star" binding:"omitempty,min=1,max=5"` // 按星级筛选，0 = 全部
+	HasImage  bool `form:"has_image"`                            // 只看有图
+}
+
+// ========== 管理端：商家回复 - PUT /admin/review/:review_id/reply ==========
+// 路径参数单独绑定（ShouldBindUri 会校验整个结构体，不能和必填的 JSON 字段放在一起）
+type ReviewIDReq struct {
+	ReviewID uint `uri:"review_id" binding:"required,min=1"`
+}
+
+type ReplyReviewReq struct {
+	ReviewID uint   `json:"-"` // 来自路径参数
+	Reply    string `json:"reply" binding:"required,max=500"`
+}
@@ -7,7 +7,7 @@ type CreateSeckillProductReq struct {
 	ProductID    uint   `json:"product_id" binding:"required,min=1"`
 	SkuID        uint   `json:"sku_id" binding:"required,min=1"`
 	SeckillPrice uint   `json:"seckill_price" binding:"required,min=1"` // 秒杀价（单位：分）
-	SeckillStock uint   `json:"seckill_stock" binding:"required,min=1"` // 秒杀库存
+	SeckillStock uint   `json:"seckill_stock" binding:"required,min=1"` // 秒杀库存（从 SKU 可售库存中预留）
 	StartTime    string `json:"start_time" binding:"required"`          // 格式："2026-01-23 10:00:00"
 	EndTime      string `json:"end_time" binding:"required"`            // 格式："2026-01-23 12:00:00"
 }
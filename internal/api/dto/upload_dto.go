@@ -0,0 +1,8 @@
+package dto
+
+// ========== 直传预签名 - POST /upload/presign ==========
+type PresignUploadReq struct {
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/gif"`
+	Size        int64  `json:"size" binding:"required,min=1"`                // 文件大小（字节）
+	SHA256      string `json:"sha256" binding:"required,len=64,hexadecimal"` // 文件内容的 SHA-256（十六进制），用于去重和存储端校验
+}
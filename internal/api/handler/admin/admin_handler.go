@@ -30,7 +30,7 @@ func AdminCreateProduct(c *gin.Context) {
 
 // 管理员更新商品库存
 func AdminUpdateProductStock(c *gin.Context) {
-	// 补充库存（运营常用）
+	// 入库/出库调整（运营常用，以增减量表示并记录流水）
 	//1.绑定请求参数
 	var req dto.UpdateProductStockReq
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -38,13 +38,13 @@ func AdminUpdateProductStock(c *gin.Context) {
 		return
 	}
 	//2.调用Service
-	err := adminService.Product.UpdateProductStock(req)
+	resp, err := adminService.Product.UpdateProductStock(req)
 	if err != nil {
 		handleServiceError(c, err)
 		return
 	}
 	//3.返回响应
-	response.Success(c, nil)
+	response.Success(c, resp)
 }
 
 // 管理员切换商品上架状态
@@ -65,3 +65,122 @@ func AdminToggleProductOnSale(c *gin.Context) {
 	//3.返回响应
 	response.Success(c, nil)
 }
+
+// 管理员查询商品浏览统计
+func AdminProductViewStats(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ProductViewStatsReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Product.ProductViewStats(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 管理员更新 SKU 价格
+func AdminUpdateSkuPrice(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.UpdateSkuPriceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Product.UpdateSkuPrice(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 管理员更新商品展示价格 / 折扣价
+func AdminUpdateProductPrice(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.UpdateProductPriceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Product.UpdateProductPrice(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 管理员替换商品 / SKU 图集
+func AdminUpdateProductImages(c *gin.Context) {
+	//1.绑定请求参数
+	var uri dto.ProductIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	var req dto.UpdateProductImagesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	req.ProductID = uri.ProductID
+	//2.调用Service
+	if err := adminService.Product.UpdateProductImages(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 管理员保存商品图文详情
+func AdminUpdateProductContent(c *gin.Context) {
+	//1.绑定请求参数
+	var uri dto.ProductIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	var req dto.UpdateProductContentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	req.ProductID = uri.ProductID
+	//2.调用Service
+	if err := adminService.Product.UpdateProductContent(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 管理员设置定时上下架
+func AdminUpdateSaleSchedule(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.UpdateSaleScheduleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Product.UpdateSaleSchedule(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
@@ -0,0 +1,28 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 匿名浏览路径分析
+func AdminViewPaths(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ViewPathsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Analytics.ViewPaths(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
@@ -0,0 +1,86 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 创建运营位
+func AdminCreateCarousel(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.CreateCarouselReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Carousel.CreateCarousel(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 更新运营位
+func AdminUpdateCarousel(c *gin.Context) {
+	//1.绑定请求参数
+	var uri dto.CarouselIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	var req dto.UpdateCarouselReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	req.ID = uri.ID
+	//2.调用Service
+	if err := adminService.Carousel.UpdateCarousel(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 删除运营位
+func AdminDeleteCarousel(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.CarouselIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Carousel.DeleteCarousel(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 运营位列表
+func AdminCarouselList(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.CarouselListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Carousel.CarouselList(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
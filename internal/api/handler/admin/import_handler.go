@@ -0,0 +1,106 @@
+package adminHandler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/pkg/sheet"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理员批量导入商品（CSV / XLSX），返回任务 ID
+func AdminImportProducts(c *gin.Context) {
+	//1.绑定请求参数
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	if file.Size > adminService.MaxImportFileSize {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "文件过大")
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, adminService.MaxImportFileSize))
+	if err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Import.CreateProductImport(file.Filename, data)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 管理员查询导入任务进度
+func AdminGetImportJob(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ImportJobIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Import.GetImportJob(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 管理员下载导入错误报告
+func AdminDownloadImportErrors(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ImportJobIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	report, err := adminService.Import.GetImportErrorReport(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回文件
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import_%d_errors.csv"`, req.JobID))
+	c.Data(200, sheet.ContentType(sheet.FormatCSV), []byte(report))
+}
+
+// 管理员导出商品目录
+func AdminExportProducts(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ExportProductsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	if req.Format == "" {
+		req.Format = sheet.FormatXLSX
+	}
+	//2.调用Service（边查边写，开始写出后出错只能中断下载）
+	fileName := fmt.Sprintf("products_%s.%s", time.Now().Format("20060102150405"), req.Format)
+	c.Header("Content-Type", sheet.ContentType(req.Format))
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	if err := adminService.Import.ExportProducts(req, c.Writer); err != nil {
+		log.Printf("❌ 导出商品失败: %v", err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,104 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SKU 库存流水
+func AdminStockMovementList(c *gin.Context) {
+	//1.绑定请求参数
+	var uri dto.SkuIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	var req dto.StockMovementListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	req.ProductSKUID = uri.ProductSKUID
+	//2.调用Service
+	resp, err := adminService.Inventory.StockMovementList(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 修改 SKU 下单预占方式
+func AdminUpdateSkuStockMode(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.UpdateSkuStockModeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Inventory.UpdateSkuStockMode(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 设置 SKU 低库存告警阈值
+func AdminUpdateSkuLowStockThreshold(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.UpdateSkuLowStockThresholdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Inventory.UpdateSkuLowStockThreshold(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 低库存 SKU 列表
+func AdminLowStockList(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.LowStockListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Inventory.LowStockList(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 低库存告警记录
+func AdminStockAlertList(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.StockAlertListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Inventory.StockAlertList(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
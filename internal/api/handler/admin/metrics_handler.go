@@ -0,0 +1,24 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 业务计数器
+func AdminGetMetrics(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.MetricsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp := adminService.Metrics.GetMetrics(req)
+	//3.返回响应
+	response.Success(c, resp)
+}
@@ -0,0 +1,45 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 订单退款
+func AdminRefundOrder(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.RefundOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.Order.RefundOrder(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 订单发货
+func AdminShipOrder(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.ShipOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.Order.ShipOrder(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
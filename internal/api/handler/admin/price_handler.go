@@ -0,0 +1,81 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 创建价格规则
+func AdminCreatePriceRule(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.CreatePriceRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.PriceRule.CreatePriceRule(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 取消价格规则
+func AdminCancelPriceRule(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.PriceRuleIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := adminService.PriceRule.CancelPriceRule(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 价格规则列表
+func AdminPriceRuleList(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.PriceRuleListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.PriceRule.PriceRuleList(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 售价历史
+func AdminPriceHistory(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.PriceHistoryReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := adminService.PriceRule.PriceHistory(req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
@@ -0,0 +1,33 @@
+package adminHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/adminService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 商家回复评价
+func AdminReplyReview(c *gin.Context) {
+	//1.绑定请求参数
+	var uri dto.ReviewIDReq
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	var req dto.ReplyReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	req.ReviewID = uri.ReviewID
+	//2.调用Service
+	if err := adminService.Review.ReplyReview(req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
@@ -10,6 +10,7 @@ import (
 )
 
 // 创建秒杀商品
+// 秒杀库存从 SKU 可售库存中预留（库存流水 seckill_reserve），SKU 可售库存不足时创建失败
 func AdminCreateSeckillProduct(c *gin.Context) {
 	//1.绑定请求参数
 	var req dto.CreateSeckillProductReq
@@ -28,6 +29,8 @@ func AdminCreateSeckillProduct(c *gin.Context) {
 }
 
 // 删除秒杀商品
+// 立即停止售卖，未售出的预留库存归还 SKU（库存流水 seckill_release）；
+// 预留机制上线前创建的活动从未扣过 SKU 库存，删除时不归还
 func AdminDeleteSeckillProduct(c *gin.Context) {
 	//1.绑定请求参数
 	var req dto.DeleteSeckillProductReq
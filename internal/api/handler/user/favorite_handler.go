@@ -0,0 +1,68 @@
+package userHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/userService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 添加收藏
+func AddFavorite(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.FavoriteReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := userService.Favorite.AddFavorite(userID, req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 取消收藏
+func RemoveFavorite(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.FavoriteReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := userService.Favorite.RemoveFavorite(userID, req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 收藏列表
+func FavoriteList(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.FavoriteListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := userService.Favorite.FavoriteList(userID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
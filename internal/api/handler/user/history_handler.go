@@ -0,0 +1,62 @@
+package userHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/userService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 浏览历史列表
+func HistoryList(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.HistoryListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := userService.History.HistoryList(userID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 删除单条浏览历史
+func DeleteHistory(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.DeleteHistoryReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := userService.History.DeleteHistory(userID, req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
+
+// 清空浏览历史
+func ClearHistory(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.调用Service
+	if err := userService.History.ClearHistory(userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//2.返回响应
+	response.Success(c, nil)
+}
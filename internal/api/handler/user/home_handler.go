@@ -0,0 +1,21 @@
+package userHandler
+
+import (
+	"xiaomi-mall/internal/service/userService"
+	"xiaomi-mall/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 首页聚合数据
+// GET /home
+func HomeIndex(c *gin.Context) {
+	//1.调用Service
+	resp, err := userService.Home.HomeIndex()
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//2.返回响应
+	response.Success(c, resp)
+}
@@ -0,0 +1,49 @@
+package userHandler
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/service/userService"
+	"xiaomi-mall/pkg/response"
+	"xiaomi-mall/pkg/xerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 通知列表
+func NotificationList(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.NotificationListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := userService.Notification.NotificationList(userID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 标记通知已读
+func ReadNotifications(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.ReadNotificationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	if err := userService.Notification.ReadNotifications(userID, req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, nil)
+}
@@ -127,3 +127,23 @@ func ConfirmOrder(c *gin.Context) {
 	//3.返回响应
 	response.Success(c, nil)
 }
+
+// 延长收货
+func ExtendConfirm(c *gin.Context) {
+	//0.获取用户ID
+	userID := c.GetUint("user_id")
+	//1.绑定请求参数
+	var req dto.ExtendConfirmReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := userService.Order.ExtendAutoConfirm(userID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
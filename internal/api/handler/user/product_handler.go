@@ -31,6 +31,8 @@ func ProductList(c *gin.Context) {
 // 商品详情查询
 // GET /products/:product_id
 func ProductDetail(c *gin.Context) {
+	//0.获取用户ID（用于统计独立访客）
+	userID := c.GetUint("user_id")
 	//1.绑定请求参数（URI 路径参数）
 	var req dto.ProductDetailReq
 	if err := c.ShouldBindUri(&req); err != nil { // ⬅️ 改为 ShouldBindUri
@@ -38,7 +40,30 @@ func ProductDetail(c *gin.Context) {
 		return
 	}
 	//2.调用Service
-	resp, err := userService.Product.ProductDetail(req)
+	resp, err := userService.Product.ProductDetail(userID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	//3.返回响应
+	response.Success(c, resp)
+}
+
+// 关联商品（买了又买）
+// GET /products/:product_id/related
+func RelatedProducts(c *gin.Context) {
+	//1.绑定请求参数
+	var req dto.RelatedProductsReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, xerr.REUQEST_PARAM_ERROR, "")
+		return
+	}
+	//2.调用Service
+	resp, err := userService.Product.RelatedProducts(req)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -0,0 +1,14 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AnalyticsRoutes(rg *gin.RouterGroup) {
+	analyticsGroup := rg.Group("/admin/analytics")
+	{
+		analyticsGroup.GET("/view_paths", adminHandler.AdminViewPaths) // 匿名浏览路径
+	}
+}
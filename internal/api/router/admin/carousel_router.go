@@ -0,0 +1,17 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CarouselRoutes(rg *gin.RouterGroup) {
+	carouselGroup := rg.Group("/admin/carousel")
+	{
+		carouselGroup.GET("", adminHandler.AdminCarouselList)
+		carouselGroup.POST("", adminHandler.AdminCreateCarousel)
+		carouselGroup.PUT("/:id", adminHandler.AdminUpdateCarousel)
+		carouselGroup.DELETE("/:id", adminHandler.AdminDeleteCarousel)
+	}
+}
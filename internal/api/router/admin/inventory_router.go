@@ -0,0 +1,15 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InventoryRoutes(rg *gin.RouterGroup) {
+	inventoryGroup := rg.Group("/admin/inventory")
+	{
+		inventoryGroup.GET("/low_stock", adminHandler.AdminLowStockList) // 可售库存低于等于阈值的 SKU
+		inventoryGroup.GET("/alerts", adminHandler.AdminStockAlertList)  // 低库存告警记录
+	}
+}
@@ -0,0 +1,14 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MetricsRoutes(rg *gin.RouterGroup) {
+	adminGroup := rg.Group("/admin")
+	{
+		adminGroup.GET("/metrics", adminHandler.AdminGetMetrics) // 业务计数器（当前实例）
+	}
+}
@@ -0,0 +1,15 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func OrderRoutes(rg *gin.RouterGroup) {
+	orderGroup := rg.Group("/admin/order")
+	{
+		orderGroup.POST("/refund", adminHandler.AdminRefundOrder) // 退款（仅已支付未发货）
+		orderGroup.POST("/ship", adminHandler.AdminShipOrder)     // 发货（到期未确认收货自动完成）
+	}
+}
@@ -0,0 +1,17 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PriceRoutes(rg *gin.RouterGroup) {
+	priceGroup := rg.Group("/admin/price")
+	{
+		priceGroup.GET("/rules", adminHandler.AdminPriceRuleList)
+		priceGroup.POST("/rules", adminHandler.AdminCreatePriceRule)
+		priceGroup.DELETE("/rules/:id", adminHandler.AdminCancelPriceRule) // 取消规则
+		priceGroup.GET("/history", adminHandler.AdminPriceHistory)         // 售价历史 / 指定时刻售价
+	}
+}
@@ -10,8 +10,21 @@ func ProductRoutes(rg *gin.RouterGroup) {
 	adminGroup := rg.Group("/admin")
 	{
 		adminGroup.POST("/product", adminHandler.AdminCreateProduct)
-		adminGroup.PUT("/product/stock", adminHandler.AdminUpdateProductStock)
+		adminGroup.PUT("/product/stock", adminHandler.AdminUpdateProductStock)                           // 库存调整（增减量 + 原因）
+		adminGroup.GET("/product/sku/:sku_id/movements", adminHandler.AdminStockMovementList)            // SKU 库存流水
+		adminGroup.PUT("/product/sku/stock_mode", adminHandler.AdminUpdateSkuStockMode)                  // SKU 下单预占方式
+		adminGroup.PUT("/product/sku/low_stock_threshold", adminHandler.AdminUpdateSkuLowStockThreshold) // SKU 低库存告警阈值
 		adminGroup.PUT("/product/on_sale", adminHandler.AdminToggleProductOnSale)
+		adminGroup.PUT("/product/sale_schedule", adminHandler.AdminUpdateSaleSchedule)           // 定时上下架
+		adminGroup.PUT("/product/price", adminHandler.AdminUpdateProductPrice)                   // 展示价 / 折扣价
+		adminGroup.PUT("/product/sku/price", adminHandler.AdminUpdateSkuPrice)                   // SKU 价格
+		adminGroup.GET("/product/:product_id/views", adminHandler.AdminProductViewStats)         // 每日浏览量/UV
+		adminGroup.PUT("/product/:product_id/images", adminHandler.AdminUpdateProductImages)     // 商品 / SKU 图集
+		adminGroup.PUT("/product/:product_id/content", adminHandler.AdminUpdateProductContent)   // 图文详情
+		adminGroup.POST("/product/import", adminHandler.AdminImportProducts)                     // 批量导入（后台任务）
+		adminGroup.GET("/product/import/:job_id", adminHandler.AdminGetImportJob)                // 导入进度
+		adminGroup.GET("/product/import/:job_id/errors", adminHandler.AdminDownloadImportErrors) // 导入错误报告
+		adminGroup.GET("/product/export", adminHandler.AdminExportProducts)                      // 导出商品目录
 		// adminGroup.POST("/login", handler.AdminLogin)
 	}
 }
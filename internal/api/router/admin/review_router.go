@@ -0,0 +1,14 @@
+package adminRouter
+
+import (
+	adminHandler "xiaomi-mall/internal/api/handler/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReviewRoutes(rg *gin.RouterGroup) {
+	reviewGroup := rg.Group("/admin/review")
+	{
+		reviewGroup.PUT("/:review_id/reply", adminHandler.AdminReplyReview) // 商家回复
+	}
+}
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"xiaomi-mall/config"
 	adminRouter "xiaomi-mall/internal/api/router/admin"
 	userRouter "xiaomi-mall/internal/api/router/user"
+	"xiaomi-mall/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,23 +16,40 @@ func InitRouter() *gin.Engine {
 	// 全局中间件
 	// r.Use(middleware.Cors())   // 跨域
 	// r.Use(middleware.Logger()) // 日志
+	r.Use(middleware.RateLimit()) // 限流（规则见配置 rate_limit.rules，支持热更新）
 
 	// 健康检查
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
+	// 本地存储的图片由 Gin 直接提供访问
+	if config.AppConfig.OSS.Driver == "local" {
+		r.Static(config.AppConfig.OSS.LocalURLPrefix, config.AppConfig.OSS.LocalDir)
+	}
+
 	// API v1 路由组
 	v1 := r.Group("/api")
 	{
-		adminRouter.ProductRoutes(v1) // 管理员商品路由
-		adminRouter.SeckillRoutes(v1) // 管理员秒杀路由
-
-		userRouter.AddressRoutes(v1) // 用户地址路由
-		userRouter.OrderRoutes(v1)   // 用户订单路由
-		userRouter.ProductRoutes(v1) // 用户商品路由
-		userRouter.SeckillRoutes(v1) // 用户秒杀路由
-		userRouter.UserRoutes(v1)    // 用户路由
+		adminRouter.ProductRoutes(v1)   // 管理员商品路由
+		adminRouter.SeckillRoutes(v1)   // 管理员秒杀路由
+		adminRouter.OrderRoutes(v1)     // 管理员订单路由
+		adminRouter.ReviewRoutes(v1)    // 管理员评价路由
+		adminRouter.AnalyticsRoutes(v1) // 管理员数据分析路由
+		adminRouter.CarouselRoutes(v1)  // 管理员运营位路由
+		adminRouter.PriceRoutes(v1)     // 管理员价格规则路由
+		adminRouter.MetricsRoutes(v1)   // 管理员业务计数器路由
+		adminRouter.InventoryRoutes(v1) // 管理员库存告警路由
+
+		userRouter.HomeRoutes(v1)     // 首页路由
+		userRouter.AddressRoutes(v1)  // 用户地址路由
+		userRouter.OrderRoutes(v1)    // 用户订单路由
+		userRouter.ProductRoutes(v1)  // 用户商品路由
+		userRouter.ReviewRoutes(v1)   // 用户评价路由
+		userRouter.FavoriteRoutes(v1) // 用户收藏 / 通知路由
+		userRouter.SeckillRoutes(v1)  // 用户秒杀路由
+		userRouter.UploadRoutes(v1)   // 上传路由
+		userRouter.UserRoutes(v1)     // 用户路由
 	}
 
 	return r
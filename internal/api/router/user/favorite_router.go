@@ -0,0 +1,26 @@
+package userRouter
+
+import (
+	userHandler "xiaomi-mall/internal/api/handler/user"
+	"xiaomi-mall/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 注册收藏、通知相关路由
+func FavoriteRoutes(rg *gin.RouterGroup) {
+	favoriteGroup := rg.Group("/favorites")
+	favoriteGroup.Use(middleware.JWTAuth()) // JWT 认证
+	{
+		favoriteGroup.GET("", userHandler.FavoriteList)                  // 收藏列表
+		favoriteGroup.POST("/:product_id", userHandler.AddFavorite)      // 添加收藏
+		favoriteGroup.DELETE("/:product_id", userHandler.RemoveFavorite) // 取消收藏
+	}
+
+	notificationGroup := rg.Group("/notifications")
+	notificationGroup.Use(middleware.JWTAuth())
+	{
+		notificationGroup.GET("", userHandler.NotificationList)       // 通知列表
+		notificationGroup.PUT("/read", userHandler.ReadNotifications) // 标记已读
+	}
+}
@@ -0,0 +1,12 @@
+package userRouter
+
+import (
+	userHandler "xiaomi-mall/internal/api/handler/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 注册首页路由（公开接口，不需要登录）
+func HomeRoutes(rg *gin.RouterGroup) {
+	rg.GET("/home", userHandler.HomeIndex)
+}
@@ -12,17 +12,24 @@ func ProductRoutes(rg *gin.RouterGroup) {
 	productGroup := rg.Group("/products")
 	productGroup.Use(middleware.JWTAuth()) // JWT 认证中间件
 	{
-		// ✅ 查询商品列表（GET + Query Params + IP限流防爬虫）
-		// productGroup.GET("", middleware.IPRateLimit(), userHandler.ProductList)
+		// ✅ 查询商品列表（GET + Query Params + IP限流防爬虫，规则见配置 rate_limit.rules）
 		productGroup.GET("", userHandler.ProductList)
 
 		// ✅ 查询商品详情（GET + 路径参数 + IP限流）
-		// productGroup.GET("/:product_id", middleware.IPRateLimit(), userHandler.ProductDetail)
 		productGroup.GET("/:product_id", userHandler.ProductDetail)
 
 		// ✅ 查询 SKU 详情（GET + 路径参数 + IP限流）
-		// productGroup.GET("/skus/:sku_id", middleware.IPRateLimit(), userHandler.SkuDetail)
 		productGroup.GET("/skus/:sku_id", userHandler.SkuDetail)
+
+		// ✅ 到货提醒（缺货 SKU 订阅，到货后站内通知）
+		productGroup.POST("/skus/:sku_id/subscription", userHandler.SubscribeStock)
+		productGroup.DELETE("/skus/:sku_id/subscription", userHandler.UnsubscribeStock)
+
+		// ✅ 商品评价列表（支持星级 / 有图筛选）
+		productGroup.GET("/:product_id/reviews", userHandler.ProductReviewList)
+
+		// ✅ 关联商品（买了又买，不足时同分类热销补齐）
+		productGroup.GET("/:product_id/related", userHandler.RelatedProducts)
 	}
 
 	// ✅ 查询分类列表（独立路由组）
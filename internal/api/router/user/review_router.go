@@ -0,0 +1,18 @@
+package userRouter
+
+import (
+	userHandler "xiaomi-mall/internal/api/handler/user"
+	"xiaomi-mall/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 注册评价相关路由
+func ReviewRoutes(rg *gin.RouterGroup) {
+	reviewGroup := rg.Group("/review")
+	reviewGroup.Use(middleware.JWTAuth()) // JWT 认证
+	{
+		reviewGroup.POST("", userHandler.CreateReview)             // 发表评价
+		reviewGroup.POST("/follow_up", userHandler.FollowUpReview) // 追评
+	}
+}
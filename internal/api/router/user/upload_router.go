@@ -0,0 +1,18 @@
+package userRouter
+
+import (
+	userHandler "xiaomi-mall/internal/api/handler/user"
+	"xiaomi-mall/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 注册上传相关路由
+func UploadRoutes(rg *gin.RouterGroup) {
+	uploadGroup := rg.Group("/upload")
+	uploadGroup.Use(middleware.JWTAuth()) // JWT 认证
+	{
+		uploadGroup.POST("/image", userHandler.UploadImage)     // 上传图片（服务端生成缩略图）
+		uploadGroup.POST("/presign", userHandler.PresignUpload) // 获取直传预签名地址（仅对象存储）
+	}
+}
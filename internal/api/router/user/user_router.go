@@ -23,6 +23,11 @@ func UserRoutes(rg *gin.RouterGroup) {
 			userID := c.GetUint("user_id")
 			response.Success(c, gin.H{"user_id": userID})
 		})
+
+		// 浏览历史
+		auth.GET("/history", userHandler.HistoryList)                  // 浏览历史列表
+		auth.DELETE("/history/:product_id", userHandler.DeleteHistory) // 删除单条
+		auth.DELETE("/history", userHandler.ClearHistory)              // 清空
 		// {
 		// 	auth.GET("/profile", userHandler.GetUserProfile)
 		// 	auth.PUT("/profile", userHandler.UpdateUserProfile)
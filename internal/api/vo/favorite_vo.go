@@ -0,0 +1,45 @@
+package vo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 收藏列表项
+type FavoriteItemVO struct {
+	ProductID     uint      `json:"product_id"`
+	Name          string    `json:"name"`
+	Title         string    `json:"title"`
+	ImgPath       string    `json:"img_path"`
+	Price         int64     `json:"price"`
+	DiscountPrice int64     `json:"discount_price"`
+	OnSale        bool      `json:"on_sale"`
+	FavoritedAt   time.Time `json:"favorited_at"` // 收藏时间
+}
+
+// 收藏列表响应
+type FavoriteListResp struct {
+	List     []FavoriteItemVO `json:"list"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
+
+// 通知列表项
+type NotificationVO struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Content   string          `json:"content"`
+	Payload   json.RawMessage `json:"payload,omitempty"` // 业务数据，按 type 解析
+	IsRead    bool            `json:"is_read"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// 通知列表响应
+type NotificationListResp struct {
+	List     []NotificationVO `json:"list"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
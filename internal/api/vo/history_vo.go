@@ -0,0 +1,41 @@
+package vo
+
+import "time"
+
+// 浏览历史项
+type HistoryItemVO struct {
+	ProductID     uint      `json:"product_id"`
+	Name          string    `json:"name"`
+	Title         string    `json:"title"`
+	ImgPath       string    `json:"img_path"`
+	Price         int64     `json:"price"`
+	DiscountPrice int64     `json:"discount_price"`
+	OnSale        bool      `json:"on_sale"`
+	ViewedAt      time.Time `json:"viewed_at"` // 最近浏览时间
+}
+
+// 浏览历史列表响应
+type HistoryListResp struct {
+	List     []HistoryItemVO `json:"list"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// 浏览路径中的一步
+type ViewStepVO struct {
+	ProductID uint      `json:"product_id"`
+	ViewedAt  time.Time `json:"viewed_at"`
+}
+
+// 匿名用户的浏览路径（按时间升序）
+type ViewPathVO struct {
+	Visitor string       `json:"visitor"` // 匿名访客标识（用户 ID 的 HMAC 摘要）
+	Steps   []ViewStepVO `json:"steps"`
+}
+
+// 浏览路径分析响应
+type ViewPathsResp struct {
+	List       []ViewPathVO `json:"list"`
+	NextCursor uint64       `json:"next_cursor"` // 为 0 表示扫描结束
+}
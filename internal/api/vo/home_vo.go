@@ -0,0 +1,71 @@
+package vo
+
+import "time"
+
+// ============ 管理端 VO ============
+
+// 运营位详情
+type CarouselVO struct {
+	ID             uint      `json:"id"`
+	Slot           string    `json:"slot"`
+	SlotCategoryID uint      `json:"slot_category_id"`
+	Title          string    `json:"title"`
+	ImgPath        string    `json:"img_path"`
+	TargetType     string    `json:"target_type"`
+	ProductID      uint      `json:"product_id"`
+	CategoryID     uint      `json:"category_id"`
+	URL            string    `json:"url"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	Sort           int       `json:"sort"`
+	Status         int8      `json:"status"` // 0:停用 1:启用 2:已过期
+}
+
+// 运营位列表响应
+type CarouselListResp struct {
+	List     []CarouselVO `json:"list"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+// ============ 首页 VO ============
+
+// 首页运营位（只返回展示需要的字段）
+type SlotVO struct {
+	ID         uint   `json:"id"`
+	Title      string `json:"title"`
+	ImgPath    string `json:"img_path"`
+	TargetType string `json:"target_type"` // product / category / url
+	ProductID  uint   `json:"product_id,omitempty"`
+	CategoryID uint   `json:"category_id,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// 分类精选
+type CategoryPickVO struct {
+	CategoryID   uint     `json:"category_id"`
+	CategoryName string   `json:"category_name"`
+	Items        []SlotVO `json:"items"`
+}
+
+// 首页秒杀活动（库存以秒杀详情接口为准）
+type HomeSeckillVO struct {
+	ID            uint      `json:"id"`
+	ProductID     uint      `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	ImgPath       string    `json:"img_path"`
+	OriginalPrice int64     `json:"original_price"`
+	SeckillPrice  uint      `json:"seckill_price"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+}
+
+// 首页聚合响应
+type HomeResp struct {
+	Carousel      []SlotVO         `json:"carousel"`       // 首页轮播
+	CategoryPicks []CategoryPickVO `json:"category_picks"` // 分类精选
+	NewArrivals   []SlotVO         `json:"new_arrivals"`   // 新品推荐
+	Seckill       []HomeSeckillVO  `json:"seckill"`        // 即将结束的秒杀活动
+	TopSellers    []ProductItemVO  `json:"top_sellers"`    // 热销商品
+}
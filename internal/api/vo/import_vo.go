@@ -0,0 +1,24 @@
+package vo
+
+import "time"
+
+// 创建导入任务响应
+type CreateImportJobResp struct {
+	JobID uint `json:"job_id"`
+}
+
+// 导入任务进度
+type ImportJobVO struct {
+	JobID          uint       `json:"job_id"`
+	Type           string     `json:"type"`
+	FileName       string     `json:"file_name"`
+	Status         int8       `json:"status"` // 0:排队中 1:执行中 2:已完成 3:失败
+	TotalRows      int        `json:"total_rows"`
+	ProcessedRows  int        `json:"processed_rows"`
+	SuccessRows    int        `json:"success_rows"`
+	FailedRows     int        `json:"failed_rows"`
+	Message        string     `json:"message"`          // 任务级错误
+	HasErrorReport bool       `json:"has_error_report"` // 有失败行时可下载错误报告
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+}
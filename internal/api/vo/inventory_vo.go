@@ -0,0 +1,66 @@
+package vo
+
+import "time"
+
+// 库存流水
+type InventoryMovementVO struct {
+	ID         uint      `json:"id"`
+	Delta      int       `json:"delta"`       // 库存变化量（正数入库，负数出库）
+	Reason     string    `json:"reason"`      // order / cancel / timeout / refund / admin_adjust / seckill_reserve / seckill_release / initial / import
+	OrderNum   string    `json:"order_num"`   // 关联订单号
+	Operator   string    `json:"operator"`    // 操作人
+	StockAfter int       `json:"stock_after"` // 变更后的库存
+	Remark     string    `json:"remark"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SKU 库存流水列表响应
+type StockMovementListResp struct {
+	ProductSkuID uint                  `json:"product_sku_id"`
+	Stock        int                   `json:"stock"`    // 当前库存
+	Reserved     int                   `json:"reserved"` // 未支付订单预占的库存
+	List         []InventoryMovementVO `json:"list"`
+	Total        int64                 `json:"total"`
+	Page         int                   `json:"page"`
+	PageSize     int                   `json:"page_size"`
+}
+
+// 低库存 SKU
+type LowStockSkuVO struct {
+	ProductID    uint   `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	ProductSkuID uint   `json:"product_sku_id"`
+	SkuTitle     string `json:"sku_title"`
+	Stock        int    `json:"stock"`
+	Reserved     int    `json:"reserved"`
+	Available    int    `json:"available"` // 可售库存
+	Threshold    int    `json:"threshold"`
+}
+
+// 低库存 SKU 列表响应
+type LowStockListResp struct {
+	List     []LowStockSkuVO `json:"list"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// 低库存告警记录
+type StockAlertVO struct {
+	ID           uint       `json:"id"`
+	ProductID    uint       `json:"product_id"`
+	ProductSkuID uint       `json:"product_sku_id"`
+	Available    int        `json:"available"` // 触发时的可售库存
+	Threshold    int        `json:"threshold"` // 触发时的阈值
+	Status       int8       `json:"status"`    // 0:告警中 1:已恢复
+	ResolvedAt   *time.Time `json:"resolved_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// 低库存告警记录列表响应
+type StockAlertListResp struct {
+	List     []StockAlertVO `json:"list"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
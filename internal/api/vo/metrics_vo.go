@@ -0,0 +1,14 @@
+package vo
+
+// 业务计数器
+type MetricVO struct {
+	Name   string           `json:"name"`
+	Help   string           `json:"help"`
+	Value  int64            `json:"value"`
+	Labels map[string]int64 `json:"labels,omitempty"` // 按标签（如 SKU ID）细分的计数
+}
+
+// 业务计数器响应（当前实例自启动以来的累计值）
+type MetricsResp struct {
+	List []MetricVO `json:"list"`
+}
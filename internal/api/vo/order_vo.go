@@ -56,6 +56,10 @@ type OrderDetailResp struct {
 	CancelTime *time.Time `json:"cancel_time,omitempty"`
 	ExpireTime time.Time  `json:"expire_time"`
 
+	// 自动确认收货
+	AutoConfirmTime *time.Time `json:"auto_confirm_time,omitempty"` // 自动确认收货时间
+	ConfirmExtended bool       `json:"confirm_extended"`            // 是否已延长收货
+
 	// 收货地址（快照）
 	Address AddressSnapshotVO `json:"address"`
 
@@ -79,6 +83,7 @@ type AddressSnapshotVO struct {
 
 // 订单商品详情项
 type OrderDetailItemVO struct {
+	ItemID       uint   `json:"item_id"` // 订单商品 ID（评价用）
 	ProductID    uint   `json:"product_id"`
 	ProductSkuID uint   `json:"product_sku_id"`
 	Title        string `json:"title"`    // 商品名（快照）
@@ -86,6 +91,7 @@ type OrderDetailItemVO struct {
 	Price        int64  `json:"price"`    // 单价（快照，分）
 	Num          int    `json:"num"`      // 数量
 	Subtotal     int64  `json:"subtotal"` // 小计 = Price * Num
+	Reviewed     bool   `json:"reviewed"` // 是否已评价
 }
 
 // ========== 支付订单响应 ==========
@@ -94,3 +100,16 @@ type PayOrderResp struct {
 	PayStatus int    `json:"pay_status"` // 1:已支付
 	TradeNo   string `json:"trade_no"`   // 支付平台交易流水号（模拟）
 }
+
+// ========== 管理端：发货响应 ==========
+type ShipOrderResp struct {
+	OrderNo         string    `json:"order_no"`
+	ShipTime        time.Time `json:"ship_time"`
+	AutoConfirmTime time.Time `json:"auto_confirm_time"` // 到期未确认收货时自动完成
+}
+
+// ========== 延长收货响应 ==========
+type ExtendConfirmResp struct {
+	OrderNo         string    `json:"order_no"`
+	AutoConfirmTime time.Time `json:"auto_confirm_time"` // 顺延后的自动确认收货时间
+}
@@ -0,0 +1,43 @@
+package vo
+
+import "time"
+
+// 价格规则
+type PriceRuleVO struct {
+	ID           uint      `json:"id"`
+	ProductID    uint      `json:"product_id"`
+	ProductSkuID uint      `json:"product_sku_id"`
+	Price        int64     `json:"price"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	Status       int8      `json:"status"` // 0:已取消 1:有效
+	Remark       string    `json:"remark"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// 价格规则列表响应
+type PriceRuleListResp struct {
+	List     []PriceRuleVO `json:"list"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+// 售价变更记录
+type PriceHistoryVO struct {
+	Price       int64     `json:"price"`      // 实际售价
+	BasePrice   int64     `json:"base_price"` // 当时的 SKU 原价
+	RuleID      uint      `json:"rule_id"`    // 生效的价格规则（0 表示按原价）
+	Source      string    `json:"source"`     // base / rule
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// 售价历史响应
+type PriceHistoryResp struct {
+	ProductSkuID uint             `json:"product_sku_id"`
+	PriceAt      *PriceHistoryVO  `json:"price_at,omitempty"` // 指定时刻生效的售价（传了 at 才返回，早于第一条记录时为空）
+	List         []PriceHistoryVO `json:"list"`
+	Total        int64            `json:"total"`
+	Page         int              `json:"page"`
+	PageSize     int              `json:"page_size"`
+}
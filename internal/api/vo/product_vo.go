@@ -1,5 +1,10 @@
 package vo
 
+import (
+	"time"
+	"xiaomi-mall/internal/pkg/types"
+)
+
 // 商品列表项（简化版）
 type ProductItemVO struct {
 	ProductID     uint   `json:"product_id"`
@@ -15,11 +20,15 @@ type ProductItemVO struct {
 
 // SKU VO
 type SkuVO struct {
-	SkuID uint   `json:"sku_id"`
-	Title string `json:"title"`
-	Price int64  `json:"price"`
-	Stock int    `json:"stock"`
-	Code  string `json:"code"`
+	SkuID         uint       `json:"sku_id"`
+	Title         string     `json:"title"`
+	Price         int64      `json:"price"`                    // 实际售价（价格规则生效时为规则价）
+	OriginalPrice int64      `json:"original_price"`           // SKU 原价
+	PriceEndTime  *time.Time `json:"price_end_time,omitempty"` // 规则价结束时间（限时价倒计时）
+	Stock         int        `json:"stock"`
+	Code          string     `json:"code"`
+	Images        []string   `json:"images"` // SKU 图集
+	Rating        RatingVO   `json:"rating"` // SKU 评分汇总
 }
 
 // 商品分类VO
@@ -42,12 +51,11 @@ type CreateProductResp struct {
 	DiscountPrice int64  `json:"discount_price"`
 }
 
-// 更新商品库存请求
-// type UpdateProductStockResp struct {
-// 	ProductID   uint   `json:"product_id"`
-// 	ProductName string `json:"product_name"`
-// 	Stock       int    `json:"stock"`
-// }
+// 调整商品库存响应
+type UpdateProductStockResp struct {
+	ProductSKUID uint `json:"product_sku_id"`
+	Stock        int  `json:"stock"` // 调整后的库存
+}
 
 // 更新商品上架状态请求
 // type UpdateProductOnSaleResp struct {
@@ -55,6 +63,19 @@ type CreateProductResp struct {
 // 	OnSale    bool `json:"on_sale"`
 // }
 
+// 商品每日浏览统计
+type DailyViewVO struct {
+	Date  string `json:"date"`  // 日期，格式 20060102
+	Views int64  `json:"views"` // 浏览量
+	UV    int64  `json:"uv"`    // 独立访客数
+}
+
+// 商品浏览统计响应
+type ProductViewStatsResp struct {
+	ProductID uint          `json:"product_id"`
+	List      []DailyViewVO `json:"list"` // 按日期升序
+}
+
 // ============ 商品查询 VO ============
 
 // 商品列表响应
@@ -67,28 +88,42 @@ type ProductListResp struct {
 
 // 商品详情响应（完整版）
 type ProductDetailResp struct {
-	ProductID     uint    `json:"product_id"`
-	Name          string  `json:"name"`
-	CategoryID    uint    `json:"category_id"`
-	CategoryName  string  `json:"category_name"`
-	Title         string  `json:"title"`
-	Info          string  `json:"info"`
-	ImgPath       string  `json:"img_path"`
-	Price         int64   `json:"price"`
-	DiscountPrice int64   `json:"discount_price"`
-	Num           int     `json:"num"`
-	ClickNum      int     `json:"click_num"`
-	OnSale        bool    `json:"on_sale"`
-	SKUs          []SkuVO `json:"skus"` // ⬅️ 包含 SKU 列表
+	ProductID     uint     `json:"product_id"`
+	Name          string   `json:"name"`
+	CategoryID    uint     `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	Title         string   `json:"title"`
+	Info          string   `json:"info"`
+	ImgPath       string   `json:"img_path"` // 封面
+	Images        []string `json:"images"`   // 商品图集
+	Price         int64    `json:"price"`
+	DiscountPrice int64    `json:"discount_price"`
+	Num           int      `json:"num"`
+	ClickNum      int      `json:"click_num"`
+	OnSale        bool     `json:"on_sale"`
+	FavoriteNum   int      `json:"favorite_num"` // 收藏数
+	IsFavorite    bool     `json:"is_favorite"`  // 当前用户是否已收藏（不缓存）
+	Rating        RatingVO `json:"rating"`       // 商品评分汇总
+	SKUs          []SkuVO  `json:"skus"`         // ⬅️ 包含 SKU 列表
+
+	Content []types.ContentBlock `json:"content"` // 图文详情
+}
+
+// 关联商品响应（买了又买，不足时用同分类热销商品补齐）
+type RelatedProductsResp struct {
+	List []ProductItemVO `json:"list"`
 }
 
 // SKU详情响应
 type SkuDetailResp struct {
-	SkuID uint   `json:"sku_id"`
-	Title string `json:"title"`
-	Price int64  `json:"price"`
-	Stock int    `json:"stock"`
-	Code  string `json:"code"`
+	SkuID         uint       `json:"sku_id"`
+	Title         string     `json:"title"`
+	Price         int64      `json:"price"`                    // 实际售价
+	OriginalPrice int64      `json:"original_price"`           // SKU 原价
+	PriceEndTime  *time.Time `json:"price_end_time,omitempty"` // 规则价结束时间
+	Stock         int        `json:"stock"`
+	Code          string     `json:"code"`
+	OnSale        bool       `json:"on_sale"` // 所属商品是否上架
 }
 
 // 商品分类列表响应
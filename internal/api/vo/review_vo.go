@@ -0,0 +1,44 @@
+package vo
+
+import "time"
+
+// 评分汇总
+type RatingVO struct {
+	Average float64 `json:"average"` // 平均分（保留 1 位小数），无评价时为 0
+	Count   int64   `json:"count"`   // 评价数
+}
+
+// 评价列表项
+type ReviewVO struct {
+	ReviewID  uint      `json:"review_id"`
+	NickName  string    `json:"nick_name"`
+	Avatar    string    `json:"avatar"`
+	SkuTitle  string    `json:"sku_title"` // 购买规格
+	Star      int       `json:"star"`
+	Content   string    `json:"content"`
+	Images    []string  `json:"images"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// 追评
+	FollowUpContent string     `json:"follow_up_content,omitempty"`
+	FollowUpImages  []string   `json:"follow_up_images,omitempty"`
+	FollowUpTime    *time.Time `json:"follow_up_time,omitempty"`
+
+	// 商家回复
+	Reply     string     `json:"reply,omitempty"`
+	ReplyTime *time.Time `json:"reply_time,omitempty"`
+}
+
+// 发表评价响应
+type CreateReviewResp struct {
+	ReviewID uint `json:"review_id"`
+}
+
+// 商品评价列表响应
+type ReviewListResp struct {
+	Rating   RatingVO   `json:"rating"` // 商品评分汇总
+	List     []ReviewVO `json:"list"`
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+}
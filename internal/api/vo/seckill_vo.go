@@ -87,6 +87,6 @@ type UserSeckillDetailVO struct {
 type CreateSeckillOrderResp struct {
 	OrderNum    string    `json:"order_num"`
 	TotalAmount int64     `json:"total_amount"`
-	ExpireTime  time.Time `json:"expire_time"` // 订单过期时间（30分钟后）
+	ExpireTime  time.Time `json:"expire_time"` // 订单过期时间（按配置的秒杀订单支付超时时间计算）
 	PayUrl      string    `json:"pay_url"`     // 支付链接（可选）
 }
@@ -0,0 +1,25 @@
+package vo
+
+import "time"
+
+// 图片上传响应
+type UploadImageResp struct {
+	Key        string `json:"key"`        // 存储 key（按内容哈希生成）
+	URL        string `json:"url"`        // 原图地址
+	ThumbURL   string `json:"thumb_url"`  // 缩略图地址
+	Width      int    `json:"width"`      // 原图宽度
+	Height     int    `json:"height"`     // 原图高度
+	Size       int64  `json:"size"`       // 文件大小（字节）
+	Duplicated bool   `json:"duplicated"` // 相同内容已上传过（未重复存储）
+}
+
+// 直传预签名响应
+type PresignUploadResp struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`                  // 上传完成后的访问地址
+	Exists    bool              `json:"exists"`               // 相同内容已存在，无需上传
+	UploadURL string            `json:"upload_url,omitempty"` // 预签名上传地址
+	Method    string            `json:"method,omitempty"`     // 上传方法（PUT）
+	Headers   map[string]string `json:"headers,omitempty"`    // 上传时必须携带的请求头
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"` // 预签名过期时间
+}
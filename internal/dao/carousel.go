@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"time"
+	"xiaomi-mall/internal/model"
+)
+
+var Carousel = new(CarouselDao)
+
+type CarouselDao struct{}
+
+// ========== 管理端：CRUD ==========
+
+// 1. 创建运营位
+func (d *CarouselDao) CreateCarousel(carousel *model.Carousel) error {
+	return DB.Create(carousel).Error
+}
+
+// 2. 根据 ID 查询运营位
+func (d *CarouselDao) GetCarouselByID(id uint) (*model.Carousel, error) {
+	var carousel model.Carousel
+	err := DB.Where("id = ?", id).First(&carousel).Error
+	return &carousel, err
+}
+
+// 3. 更新运营位
+func (d *CarouselDao) UpdateCarousel(id uint, updates map[string]interface{}) error {
+	return DB.Model(&model.Carousel{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// 4. 删除运营位
+func (d *CarouselDao) DeleteCarousel(id uint) error {
+	return DB.Delete(&model.Carousel{}, id).Error
+}
+
+// 5. 运营位列表（分页 + 运营位/状态筛选）
+func (d *CarouselDao) GetCarouselList(slot string, status *int8, page, pageSize int) ([]*model.Carousel, int64, error) {
+	var carousels []*model.Carousel
+	var total int64
+
+	query := DB.Model(&model.Carousel{})
+	if slot != "" {
+		query = query.Where("slot = ?", slot)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("slot ASC, sort ASC, id DESC").Limit(pageSize).Offset(offset).Find(&carousels).Error
+	return carousels, total, err
+}
+
+// ========== 用户端：首页展示 ==========
+
+// 6. 查询当前生效的运营位（已启用且在展示时间窗口内）
+func (d *CarouselDao) GetActiveCarousels(now time.Time) (carousels []*model.Carousel, err error) {
+	err = DB.Model(&model.Carousel{}).
+		Where("status = ? AND start_time <= ? AND end_time > ?", 1, now, now).
+		Order("sort ASC, id DESC").Find(&carousels).Error
+	return
+}
+
+// ========== 定时任务 ==========
+
+// 7. 标记已过期的运营位
+func (d *CarouselDao) ExpireCarousels(now time.Time) (int64, error) {
+	result := DB.Model(&model.Carousel{}).
+		Where("status = ? AND end_time <= ?", 1, now).
+		Update("status", 2)
+	return result.RowsAffected, result.Error
+}
+
+// 8. 统计 (from, to] 区间内开始展示的运营位数量
+func (d *CarouselDao) CountStartedBetween(from, to time.Time) (count int64, err error) {
+	err = DB.Model(&model.Carousel{}).
+		Where("status = ? AND start_time > ? AND start_time <= ?", 1, from, to).
+		Count(&count).Error
+	return
+}
@@ -0,0 +1,147 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"xiaomi-mall/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var Favorite = new(FavoriteDao)
+
+type FavoriteDao struct{}
+
+// ============ 收藏（MySQL） ============
+
+// 1. 添加收藏（已收藏时不重复计数）
+func (d *FavoriteDao) AddFavorite(userID, productID uint) (added bool, err error) {
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.Favorite{UserID: userID, ProductID: productID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // 已收藏
+		}
+		added = true
+		return tx.Model(&model.Product{}).Where("id = ?", productID).
+			UpdateColumn("favorite_num", gorm.Expr("favorite_num + 1")).Error
+	})
+	return
+}
+
+// 2. 取消收藏
+func (d *FavoriteDao) RemoveFavorite(userID, productID uint) (removed bool, err error) {
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&model.Favorite{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // 未收藏
+		}
+		removed = true
+		return tx.Model(&model.Product{}).Where("id = ? AND favorite_num > 0", productID).
+			UpdateColumn("favorite_num", gorm.Expr("favorite_num - 1")).Error
+	})
+	return
+}
+
+// 3. 用户收藏列表（按收藏时间倒序）
+func (d *FavoriteDao) GetUserFavorites(userID uint, page, pageSize int) ([]*model.Favorite, int64, error) {
+	var favorites []*model.Favorite
+	var total int64
+
+	query := DB.Model(&model.Favorite{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&favorites).Error
+	return favorites, total, err
+}
+
+// 4. 收藏了某商品的所有用户（降价通知用）
+func (d *FavoriteDao) GetFavoriteUserIDs(productID uint) (userIDs []uint, err error) {
+	err = DB.Model(&model.Favorite{}).Where("product_id = ?", productID).Pluck("user_id", &userIDs).Error
+	return
+}
+
+// ============ 收藏缓存（Redis Set） ============
+// favorite:user:{user_id}  Set  用户收藏的商品 ID
+// 集合里固定放一个占位成员 "0"，用来区分「缓存未加载」和「没有任何收藏」
+
+const (
+	favoriteSetPlaceholder = "0"
+	favoriteSetTTL         = 24 * time.Hour
+)
+
+func favoriteSetKey(userID uint) string {
+	return fmt.Sprintf("favorite:user:%d", userID)
+}
+
+// 5. 判断是否已收藏（缓存未加载时从 MySQL 加载整个集合）
+func (d *FavoriteDao) IsFavorite(ctx context.Context, userID, productID uint) (bool, error) {
+	key := favoriteSetKey(userID)
+
+	exists, err := Rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if exists == 0 {
+		if err := d.loadFavoriteSet(ctx, userID); err != nil {
+			return false, err
+		}
+	}
+
+	return Rdb.SIsMember(ctx, key, productID).Result()
+}
+
+// 6. 收藏变更后同步缓存（缓存未加载时不处理，下次读取时加载）
+func (d *FavoriteDao) SyncFavoriteCache(ctx context.Context, userID, productID uint, favorite bool) error {
+	script := `
+		if redis.call('EXISTS', KEYS[1]) == 0 then
+			return 0
+		end
+		if ARGV[2] == '1' then
+			redis.call('SADD', KEYS[1], ARGV[1])
+		else
+			redis.call('SREM', KEYS[1], ARGV[1])
+		end
+		return 1
+	`
+	flag := "0"
+	if favorite {
+		flag = "1"
+	}
+	return Rdb.Eval(ctx, script, []string{favoriteSetKey(userID)}, productID, flag).Err()
+}
+
+// loadFavoriteSet 从 MySQL 加载用户收藏集合到 Redis
+func (d *FavoriteDao) loadFavoriteSet(ctx context.Context, userID uint) error {
+	var productIDs []uint
+	err := DB.Model(&model.Favorite{}).Where("user_id = ?", userID).Pluck("product_id", &productIDs).Error
+	if err != nil {
+		return err
+	}
+
+	members := make([]interface{}, 0, len(productIDs)+1)
+	members = append(members, favoriteSetPlaceholder)
+	for _, id := range productIDs {
+		members = append(members, strconv.FormatUint(uint64(id), 10))
+	}
+
+	key := favoriteSetKey(userID)
+	pipe := Rdb.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.SAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, favoriteSetTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ============ 用户浏览历史（Redis ZSET） ============
+// history:user:{user_id}  ZSET  member = 商品 ID，score = 最近一次浏览时间（毫秒）
+// 同一商品重复浏览只更新时间，每个用户最多保留最近 100 条
+
+var History = new(HistoryDao)
+
+type HistoryDao struct{}
+
+const (
+	historyKeyPrefix = "history:user:"
+	historyMaxSize   = 100
+	historyTTL       = 90 * 24 * time.Hour
+)
+
+func historyKey(userID uint) string {
+	return fmt.Sprintf("%s%d", historyKeyPrefix, userID)
+}
+
+// HistoryEntry 一条浏览记录
+type HistoryEntry struct {
+	ProductID uint
+	ViewedAt  time.Time
+}
+
+// 1. 记录浏览（超过上限时裁掉最早的记录）
+func (d *HistoryDao) RecordHistory(ctx context.Context, userID, productID uint, viewedAt time.Time) error {
+	key := historyKey(userID)
+	pipe := Rdb.Pipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(viewedAt.UnixMilli()), Member: productID})
+	pipe.ZRemRangeByRank(ctx, key, 0, -historyMaxSize-1)
+	pipe.Expire(ctx, key, historyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 2. 分页查询浏览历史（按浏览时间倒序）
+func (d *HistoryDao) GetHistory(ctx context.Context, userID uint, page, pageSize int) ([]HistoryEntry, int64, error) {
+	key := historyKey(userID)
+	start := int64((page - 1) * pageSize)
+	stop := start + int64(pageSize) - 1
+
+	pipe := Rdb.Pipeline()
+	totalCmd := pipe.ZCard(ctx, key)
+	listCmd := pipe.ZRevRangeWithScores(ctx, key, start, stop)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, err
+	}
+
+	return toHistoryEntries(listCmd.Val()), totalCmd.Val(), nil
+}
+
+// 3. 删除单条浏览记录
+func (d *HistoryDao) RemoveHistory(ctx context.Context, userID, productID uint) error {
+	return Rdb.ZRem(ctx, historyKey(userID), productID).Err()
+}
+
+// 4. 清空浏览历史
+func (d *HistoryDao) ClearHistory(ctx context.Context, userID uint) error {
+	return Rdb.Del(ctx, historyKey(userID)).Err()
+}
+
+// 5. 按游标扫描用户浏览历史（管理端浏览路径分析用，返回按时间升序的路径）
+func (d *HistoryDao) ScanHistories(ctx context.Context, cursor uint64, count int64) (map[uint][]HistoryEntry, uint64, error) {
+	keys, next, err := Rdb.Scan(ctx, cursor, historyKeyPrefix+"*", count).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pipe := Rdb.Pipeline()
+	cmds := make(map[uint]*redis.ZSliceCmd, len(keys))
+	for _, key := range keys {
+		userID, err := strconv.ParseUint(strings.TrimPrefix(key, historyKeyPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		cmds[uint(userID)] = pipe.ZRangeWithScores(ctx, key, 0, -1)
+	}
+	if len(cmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, 0, err
+		}
+	}
+
+	paths := make(map[uint][]HistoryEntry, len(cmds))
+	for userID, cmd := range cmds {
+		if entries := toHistoryEntries(cmd.Val()); len(entries) > 0 {
+			paths[userID] = entries
+		}
+	}
+	return paths, next, nil
+}
+
+func toHistoryEntries(zs []redis.Z) []HistoryEntry {
+	entries := make([]HistoryEntry, 0, len(zs))
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		productID, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, HistoryEntry{
+			ProductID: uint(productID),
+			ViewedAt:  time.UnixMilli(int64(z.Score)),
+		})
+	}
+	return entries
+}
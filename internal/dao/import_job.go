@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"time"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+)
+
+var ImportJob = new(ImportJobDao)
+
+type ImportJobDao struct{}
+
+// CreateJob 创建导入任务
+func (d *ImportJobDao) CreateJob(job *model.ImportJob) error {
+	return DB.Create(job).Error
+}
+
+// GetJob 查询导入任务
+func (d *ImportJobDao) GetJob(jobID uint) (job *model.ImportJob, err error) {
+	err = DB.Model(&model.ImportJob{}).Where("id = ?", jobID).First(&job).Error
+	return
+}
+
+// Start 标记任务开始执行
+func (d *ImportJobDao) Start(jobID uint, totalRows int) error {
+	return DB.Model(&model.ImportJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":     constants.IMPORT_STATUS_RUNNING,
+			"total_rows": totalRows,
+		}).Error
+}
+
+// UpdateProgress 更新任务进度
+func (d *ImportJobDao) UpdateProgress(jobID uint, processed, success, failed int) error {
+	return DB.Model(&model.ImportJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"processed_rows": processed,
+			"success_rows":   success,
+			"failed_rows":    failed,
+		}).Error
+}
+
+// Finish 结束任务（status 为已完成或失败）
+func (d *ImportJobDao) Finish(jobID uint, status int8, message, errorReport string) error {
+	return DB.Model(&model.ImportJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"message":      message,
+			"error_report": errorReport,
+			"finished_at":  time.Now(),
+		}).Error
+}
+
+// FailUnfinished 把未结束的任务标记为失败（服务重启后这些任务不会再继续执行）
+func (d *ImportJobDao) FailUnfinished(message string) (int64, error) {
+	result := DB.Model(&model.ImportJob{}).
+		Where("status IN (?)", []int8{constants.IMPORT_STATUS_PENDING, constants.IMPORT_STATUS_RUNNING}).
+		Updates(map[string]interface{}{
+			"status":      constants.IMPORT_STATUS_FAILED,
+			"message":     message,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ============ 导入任务互斥锁（Redis，多实例间同一时间只执行一个导入任务） ============
+// 导入按 SPU / SKU 编码 upsert，并发执行时两个任务可能同时创建同一编码的商品
+
+const importLockKey = "lock:import:product"
+
+// TryLock 尝试获取导入锁（token 标识持有者，只有持有者能续期和释放）
+func (d *ImportJobDao) TryLock(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	return Rdb.SetNX(ctx, importLockKey, token, ttl).Result()
+}
+
+// RenewLock 续期导入锁（锁已不属于 token 时返回 false）
+func (d *ImportJobDao) RenewLock(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	script := `
+		if redis.call('GET', KEYS[1]) == ARGV[1] then
+			return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+		end
+		return 0
+	`
+	result, err := Rdb.Eval(ctx, script, []string{importLockKey}, token, ttl.Milliseconds()).Int()
+	return result == 1, err
+}
+
+// Unlock 释放导入锁（只释放 token 自己持有的锁）
+func (d *ImportJobDao) Unlock(ctx context.Context, token string) error {
+	script := `
+		if redis.call('GET', KEYS[1]) == ARGV[1] then
+			return redis.call('DEL', KEYS[1])
+		end
+		return 0
+	`
+	return Rdb.Eval(ctx, script, []string{importLockKey}, token).Err()
+}
@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"fmt"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm"
+)
+
+// ============ 库存流水 ============
+// 所有 product_skus.stock 的变更都必须经过这里（或在同一事务内调用 RecordMovement），
+// 保证每次变更都留下一条流水
+
+var Inventory = new(InventoryDao)
+
+type InventoryDao struct{}
+
+// StockChange 一次库存变更
+type StockChange struct {
+	SkuID    uint
+	Delta    int    // 变化量（正数回补，负数扣减）
+	Reason   string // constants.STOCK_REASON_*
+	OrderNum string
+	Operator string
+	Remark   string
+}
+
+// 1. 增减库存并记录流水（事务内调用，扣减不能占用已被订单预占的库存，可售库存不足时返回 nil）
+func (d *InventoryDao) ChangeStock(tx *gorm.DB, change StockChange) (*model.InventoryMovement, error) {
+	result := tx.Model(&model.ProductSku{}).
+		Where("id = ? AND stock - reserved + ? >= 0", change.SkuID, change.Delta).
+		Updates(map[string]interface{}{
+			"stock":   gorm.Expr("stock + ?", change.Delta),
+			"version": gorm.Expr("version + ?", 1),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return d.RecordMovement(tx, change)
+}
+
+// 2. 记录库存流水（库存已在同一事务内更新，读取更新后的库存作为结果库存）
+func (d *InventoryDao) RecordMovement(tx *gorm.DB, change StockChange) (*model.InventoryMovement, error) {
+	var sku model.ProductSku
+	if err := tx.Select("id", "product_id", "stock").Where("id = ?", change.SkuID).First(&sku).Error; err != nil {
+		return nil, err
+	}
+	movement := &model.InventoryMovement{
+		ProductID:    sku.ProductID,
+		ProductSkuID: sku.ID,
+		Delta:        change.Delta,
+		Reason:       change.Reason,
+		OrderNum:     change.OrderNum,
+		Operator:     change.Operator,
+		StockAfter:   sku.Stock,
+		Remark:       change.Remark,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// 3. 记录新建 SKU 的初始库存（SKU 已在同一事务内创建）
+func (d *InventoryDao) RecordInitialStocks(tx *gorm.DB, skus []*model.ProductSku) error {
+	movements := make([]*model.InventoryMovement, 0, len(skus))
+	for _, sku := range skus {
+		if sku.Stock == 0 {
+			continue
+		}
+		movements = append(movements, &model.InventoryMovement{
+			ProductID:    sku.ProductID,
+			ProductSkuID: sku.ID,
+			Delta:        sku.Stock,
+			Reason:       constants.STOCK_REASON_INITIAL,
+			StockAfter:   sku.Stock,
+		})
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+	return tx.Create(movements).Error
+}
+
+// 4. SKU 库存流水列表（按时间倒序，可按原因筛选）
+func (d *InventoryDao) GetMovementList(skuID uint, reason string, page, pageSize int) ([]*model.InventoryMovement, int64, error) {
+	var movements []*model.InventoryMovement
+	var total int64
+
+	query := DB.Model(&model.InventoryMovement{}).Where("product_sku_id = ?", skuID)
+	if reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&movements).Error
+	return movements, total, err
+}
+
+// 5. 是否存在指定关联单号和原因的库存流水（事务内调用）
+func (d *InventoryDao) HasMovement(tx *gorm.DB, orderNum, reason string) (bool, error) {
+	var count int64
+	err := tx.Model(&model.InventoryMovement{}).
+		Where("order_num = ? AND reason = ?", orderNum, reason).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UserOperator 用户操作的流水操作人
+func UserOperator(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
@@ -52,4 +52,13 @@ func InitMySQL() {
 		log.Fatalf("❌ 数据库迁移失败: %v", err)
 	}
 	fmt.Println("✅ 数据库迁移完成！")
+
+	// 回填自动确认上线前发货的订单（否则到期查询和延长收货都会跳过这些订单）
+	rows, err := Order.BackfillAutoConfirmTime(config.AppConfig.Order.AutoConfirmDays)
+	if err != nil {
+		log.Fatalf("❌ 回填自动确认时间失败: %v", err)
+	}
+	if rows > 0 {
+		fmt.Printf("✅ 回填 %d 个已发货订单的自动确认时间\n", rows)
+	}
 }
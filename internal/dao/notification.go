@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"xiaomi-mall/internal/model"
+)
+
+var Notification = new(NotificationDao)
+
+type NotificationDao struct{}
+
+// ========== 批量创建通知（每批 500 条）==========
+func (d *NotificationDao) BatchCreate(notifications []*model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return DB.CreateInBatches(notifications, 500).Error
+}
+
+// ========== 用户通知列表（分页，可只看未读）==========
+func (d *NotificationDao) GetUserNotifications(userID uint, unreadOnly bool, page, pageSize int) ([]*model.Notification, int64, error) {
+	var notifications []*model.Notification
+	var total int64
+
+	query := DB.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// ========== 标记已读（ids 为空时全部标记）==========
+func (d *NotificationDao) MarkRead(userID uint, ids []uint) error {
+	query := DB.Model(&model.Notification{}).Where("user_id = ? AND is_read = ?", userID, false)
+	if len(ids) > 0 {
+		query = query.Where("id IN (?)", ids)
+	}
+	return query.Update("is_read", true).Error
+}
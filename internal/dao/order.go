@@ -35,13 +35,20 @@ func (d *OrderDao) GetOrderByOrderNum(orderNum string) (*model.Order, error) {
 	return &order, err
 }
 
-// ========== 查询订单详情 ==========
-func (d *OrderDao) GetOrderItems(orderNum string) ([]*model.OrderItem, error) {
+// ========== 查询订单详情（事务内传入 tx，保证读到的商品与订单状态变更一致）==========
+func (d *OrderDao) GetOrderItems(tx *gorm.DB, orderNum string) ([]*model.OrderItem, error) {
 	var items []*model.OrderItem
-	err := DB.Where("order_num = ?", orderNum).Find(&items).Error
+	err := tx.Where("order_num = ?", orderNum).Find(&items).Error
 	return items, err
 }
 
+// ========== 根据 ID 查询订单商品 ==========
+func (d *OrderDao) GetOrderItemByID(itemID uint) (*model.OrderItem, error) {
+	var item model.OrderItem
+	err := DB.Where("id = ?", itemID).First(&item).Error
+	return &item, err
+}
+
 // ========== 更新订单状态（乐观锁）==========
 func (d *OrderDao) UpdateOrderStatus(tx *gorm.DB, orderNum string, status int, version int) (int64, error) {
 	result := tx.Model(&model.Order{}).
@@ -54,6 +61,21 @@ func (d *OrderDao) UpdateOrderStatus(tx *gorm.DB, orderNum string, status int, v
 	return result.RowsAffected, result.Error
 }
 
+// ========== 超时关单（乐观锁）==========
+// 只关闭待支付的订单，已支付/已取消的订单返回 0
+func (d *OrderDao) CloseOrder(tx *gorm.DB, orderNum string, version int) (int64, error) {
+	now := time.Now()
+	result := tx.Model(&model.Order{}).
+		Where("order_num = ? AND order_status = 0 AND pay_status = 0 AND version = ?", orderNum, version).
+		Updates(map[string]interface{}{
+			"order_status": 4, // 已取消
+			"cancel_time":  &now,
+			"version":      version + 1,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
 // ========== 支付订单（乐观锁）==========
 func (d *OrderDao) PayOrder(tx *gorm.DB, orderNum string, payType int, tradeNo string, version int) (int64, error) {
 	now := time.Now()
@@ -111,3 +133,82 @@ func (d *OrderDao) ConfirmOrder(tx *gorm.DB, orderNum string, version int) (int6
 
 	return result.RowsAffected, result.Error
 }
+
+// ========== 发货（乐观锁）==========
+// 只允许已支付未发货的订单发货
+func (d *OrderDao) ShipOrder(tx *gorm.DB, orderNum, trackingNumber string, autoConfirmTime time.Time, version int) (int64, error) {
+	now := time.Now()
+	result := tx.Model(&model.Order{}).
+		Where("order_num = ? AND pay_status = 1 AND order_status = 1 AND version = ?", orderNum, version).
+		Updates(map[string]interface{}{
+			"order_status":      2, // 已发货
+			"tracking_number":   trackingNumber,
+			"ship_time":         &now,
+			"auto_confirm_time": &autoConfirmTime,
+			"version":           version + 1,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// ========== 延长收货（乐观锁，每个订单只能延长一次）==========
+func (d *OrderDao) ExtendAutoConfirm(tx *gorm.DB, orderNum string, autoConfirmTime time.Time, version int) (int64, error) {
+	result := tx.Model(&model.Order{}).
+		Where("order_num = ? AND order_status = 2 AND confirm_extended = ? AND version = ?", orderNum, false, version).
+		Updates(map[string]interface{}{
+			"auto_confirm_time": &autoConfirmTime,
+			"confirm_extended":  true,
+			"version":           version + 1,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// ========== 退款（乐观锁）==========
+// 只允许已支付未发货的订单退款
+func (d *OrderDao) RefundOrder(tx *gorm.DB, orderNum string, version int) (int64, error) {
+	now := time.Now()
+	result := tx.Model(&model.Order{}).
+		Where("order_num = ? AND pay_status = 1 AND order_status = 1 AND version = ?", orderNum, version).
+		Updates(map[string]interface{}{
+			"pay_status":   2, // 已退款
+			"order_status": 4, // 已取消
+			"cancel_time":  &now,
+			"version":      version + 1,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// ========== 查询已过期仍待支付的订单 ==========
+func (d *OrderDao) GetOverdueOrderNums(before time.Time, limit int) (orderNums []string, err error) {
+	err = DB.Model(&model.Order{}).
+		Where("order_status = 0 AND pay_status = 0 AND expire_time <= ?", before).
+		Order("expire_time").Limit(limit).Pluck("order_num", &orderNums).Error
+	return
+}
+
+// ========== 分页查询待支付订单（按 ID 游标，用于重建延迟队列）==========
+func (d *OrderDao) GetPendingOrdersAfter(afterID uint, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := DB.Select("id", "order_num", "expire_time").
+		Where("id > ? AND order_status = 0 AND pay_status = 0", afterID).
+		Order("id").Limit(limit).Find(&orders).Error
+	return orders, err
+}
+
+// ========== 回填自动确认时间（自动确认上线前发货的订单没有 auto_confirm_time，按发货时间计算；没有发货时间的按最后更新时间）==========
+func (d *OrderDao) BackfillAutoConfirmTime(days int) (int64, error) {
+	result := DB.Model(&model.Order{}).
+		Where("order_status = 2 AND auto_confirm_time IS NULL").
+		UpdateColumn("auto_confirm_time", gorm.Expr("DATE_ADD(COALESCE(ship_time, updated_at), INTERVAL ? DAY)", days))
+	return result.RowsAffected, result.Error
+}
+
+// ========== 查询已到自动确认时间仍未确认收货的订单 ==========
+func (d *OrderDao) GetAutoConfirmDueOrderNums(before time.Time, limit int) (orderNums []string, err error) {
+	err = DB.Model(&model.Order{}).
+		Where("order_status = 2 AND auto_confirm_time <= ?", before).
+		Order("auto_confirm_time").Limit(limit).Pluck("order_num", &orderNums).Error
+	return
+}
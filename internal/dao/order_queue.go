@@ -0,0 +1,20 @@
+package dao
+
+import "xiaomi-mall/internal/pkg/delayqueue"
+
+// ============ 订单延迟队列 ============
+// 下单、支付、发货等业务通过 Add / Remove 排期，consumer 启动时调用 Start 消费；
+// 统一经过 delayqueue 读写，移除成员时会同时清除失败次数
+
+var (
+	OrderTimeoutQueue *delayqueue.Queue // 超时关单
+	PayReminderQueue  *delayqueue.Queue // 待支付提醒
+	AutoConfirmQueue  *delayqueue.Queue // 自动确认收货
+)
+
+// initOrderQueues 创建订单延迟队列（Redis 连接建立后调用）
+func initOrderQueues() {
+	OrderTimeoutQueue = delayqueue.New(Rdb, "order_timeout", "order:delay:queue")
+	PayReminderQueue = delayqueue.New(Rdb, "pay_reminder", "order:remind:queue")
+	AutoConfirmQueue = delayqueue.New(Rdb, "auto_confirm", "order:confirm:queue")
+}
@@ -0,0 +1,228 @@
+package dao
+
+import (
+	"context"
+	"sort"
+	"time"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var Price = new(PriceDao)
+
+type PriceDao struct{}
+
+// ============ 价格规则 ============
+
+// 1. 创建价格规则
+func (d *PriceDao) CreateRule(rule *model.PriceRule) error {
+	return DB.Create(rule).Error
+}
+
+// 2. 根据 ID 查询价格规则
+func (d *PriceDao) GetRuleByID(id uint) (*model.PriceRule, error) {
+	var rule model.PriceRule
+	err := DB.Where("id = ?", id).First(&rule).Error
+	return &rule, err
+}
+
+// 3. 取消价格规则
+func (d *PriceDao) CancelRule(id uint) (int64, error) {
+	result := DB.Model(&model.PriceRule{}).
+		Where("id = ? AND status = ?", id, constants.PRICE_RULE_STATUS_ACTIVE).
+		Update("status", constants.PRICE_RULE_STATUS_CANCELLED)
+	return result.RowsAffected, result.Error
+}
+
+// 4. 价格规则列表（按 SKU 筛选，分页）
+func (d *PriceDao) GetRuleList(skuID uint, status *int8, page, pageSize int) ([]*model.PriceRule, int64, error) {
+	var rules []*model.PriceRule
+	var total int64
+
+	query := DB.Model(&model.PriceRule{})
+	if skuID > 0 {
+		query = query.Where("product_sku_id = ?", skuID)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("start_time DESC, id DESC").Limit(pageSize).Offset(offset).Find(&rules).Error
+	return rules, total, err
+}
+
+// 5. 判断 SKU 在 [start, end) 内是否已有有效规则
+func (d *PriceDao) HasOverlappingRule(skuID uint, start, end time.Time) (bool, error) {
+	var count int64
+	err := DB.Model(&model.PriceRule{}).
+		Where("product_sku_id = ? AND status = ?", skuID, constants.PRICE_RULE_STATUS_ACTIVE).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 6. 查询 at 时刻生效的规则（key 为 SKU ID）
+func (d *PriceDao) GetEffectiveRules(skuIDs []uint, at time.Time) (map[uint]*model.PriceRule, error) {
+	result := make(map[uint]*model.PriceRule, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return result, nil
+	}
+	var rules []*model.PriceRule
+	err := DB.Model(&model.PriceRule{}).
+		Where("product_sku_id IN (?) AND status = ?", skuIDs, constants.PRICE_RULE_STATUS_ACTIVE).
+		Where("start_time <= ? AND end_time > ?", at, at).
+		Order("start_time ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		result[rule.ProductSkuID] = rule // 规则不重叠，正常情况下每个 SKU 只有一条；万一重叠以最晚开始的为准
+	}
+	return result, nil
+}
+
+// RuleBoundary 价格规则的一次开始或结束（SKU 售价在 At 时刻可能变化）
+type RuleBoundary struct {
+	SkuID uint
+	At    time.Time
+}
+
+// 7. 查询 (from, to] 内规则的开始和结束时刻（按时间升序，同一 SKU 同一时刻只返回一条，定时任务用）
+func (d *PriceDao) GetRuleBoundariesBetween(from, to time.Time) ([]RuleBoundary, error) {
+	var starts, ends []RuleBoundary
+	err := DB.Model(&model.PriceRule{}).
+		Select("product_sku_id AS sku_id, start_time AS at").
+		Where("status = ? AND start_time > ? AND start_time <= ?", constants.PRICE_RULE_STATUS_ACTIVE, from, to).
+		Scan(&starts).Error
+	if err != nil {
+		return nil, err
+	}
+	err = DB.Model(&model.PriceRule{}).
+		Select("product_sku_id AS sku_id, end_time AS at").
+		Where("status = ? AND end_time > ? AND end_time <= ?", constants.PRICE_RULE_STATUS_ACTIVE, from, to).
+		Scan(&ends).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[RuleBoundary]bool, len(starts)+len(ends))
+	boundaries := make([]RuleBoundary, 0, len(starts)+len(ends))
+	for _, b := range append(starts, ends...) {
+		if !seen[b] {
+			seen[b] = true
+			boundaries = append(boundaries, b)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].At.Before(boundaries[j].At) })
+	return boundaries, nil
+}
+
+// ============ 售价历史 ============
+
+// 8. 批量追加售价变更记录
+func (d *PriceDao) CreateHistories(histories []*model.PriceHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	return DB.Create(histories).Error
+}
+
+// 9. 批量查询 at 时刻 SKU 的售价记录（每个 SKU 取 at 之前最后一条，key 为 SKU ID）
+func (d *PriceDao) GetHistoriesAt(skuIDs []uint, at time.Time) (map[uint]*model.PriceHistory, error) {
+	result := make(map[uint]*model.PriceHistory, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return result, nil
+	}
+	var histories []*model.PriceHistory
+	err := DB.Model(&model.PriceHistory{}).
+		Where("(product_sku_id, effective_at) IN (?)", DB.Model(&model.PriceHistory{}).
+			Select("product_sku_id, MAX(effective_at)").
+			Where("product_sku_id IN (?) AND effective_at <= ?", skuIDs, at).
+			Group("product_sku_id")).
+		Order("id ASC").
+		Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, history := range histories {
+		result[history.ProductSkuID] = history // 同一时刻有多条时以最后写入的为准
+	}
+	return result, nil
+}
+
+// 10. 查询 at 时刻 SKU 的售价记录（at 之前最后一条，不存在时返回 nil）
+func (d *PriceDao) GetHistoryAt(skuID uint, at time.Time) (*model.PriceHistory, error) {
+	var histories []*model.PriceHistory
+	err := DB.Model(&model.PriceHistory{}).
+		Where("product_sku_id = ? AND effective_at <= ?", skuID, at).
+		Order("effective_at DESC, id DESC").
+		Limit(1).
+		Find(&histories).Error
+	if err != nil || len(histories) == 0 {
+		return nil, err
+	}
+	return histories[0], nil
+}
+
+// 11. 查询 SKU 的售价历史（按生效时间倒序，分页）
+func (d *PriceDao) GetHistoryList(skuID uint, page, pageSize int) ([]*model.PriceHistory, int64, error) {
+	var histories []*model.PriceHistory
+	var total int64
+
+	query := DB.Model(&model.PriceHistory{}).Where("product_sku_id = ?", skuID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("effective_at DESC, id DESC").Limit(pageSize).Offset(offset).Find(&histories).Error
+	return histories, total, err
+}
+
+// ============ 价格规则排期（Redis） ============
+// 水位记录已处理到的时刻，停机期间跨过的规则边界在重启后补齐；
+// 多实例时通过互斥锁保证同一时间只有一个实例处理，避免重复记录和重复通知
+
+const (
+	priceRuleWatermarkKey = "price:rule:watermark"
+	priceRuleLockKey      = "lock:price:rule"
+)
+
+// GetRuleWatermark 查询排期任务的水位（不存在时 ok 为 false）
+func (d *PriceDao) GetRuleWatermark(ctx context.Context) (at time.Time, ok bool, err error) {
+	ms, err := Rdb.Get(ctx, priceRuleWatermarkKey).Int64()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.UnixMilli(ms), true, nil
+}
+
+// SetRuleWatermark 更新排期任务的水位
+func (d *PriceDao) SetRuleWatermark(ctx context.Context, at time.Time) error {
+	return Rdb.Set(ctx, priceRuleWatermarkKey, at.UnixMilli(), 0).Err()
+}
+
+// TryLockRuleSchedule 尝试获取排期任务锁（token 标识持有者）
+func (d *PriceDao) TryLockRuleSchedule(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	return Rdb.SetNX(ctx, priceRuleLockKey, token, ttl).Result()
+}
+
+// UnlockRuleSchedule 释放排期任务锁（只释放 token 自己持有的锁）
+func (d *PriceDao) UnlockRuleSchedule(ctx context.Context, token string) error {
+	script := `
+		if redis.call('GET', KEYS[1]) == ARGV[1] then
+			return redis.call('DEL', KEYS[1])
+		end
+		return 0
+	`
+	return Rdb.Eval(ctx, script, []string{priceRuleLockKey}, token).Err()
+}
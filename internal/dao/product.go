@@ -1,9 +1,12 @@
 package dao
 
 import (
+	"context"
+	"time"
 	"xiaomi-mall/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var Product = new(ProductDao)
@@ -80,10 +83,78 @@ func (d *ProductDao) GetProductByID(productID uint) (product *model.Product, err
 	return
 }
 
-// 3. 增加商品点击量（浏览统计）
-func (d *ProductDao) IncrementClickNum(productID uint) error {
-	return DB.Model(&model.Product{}).Where("id = ?", productID).
-		UpdateColumn("click_num", gorm.Expr("click_num + ?", 1)).Error
+// 3. 批量增加商品点击量（浏览统计落库，每条 SQL 最多更新 500 个商品）
+// 同一 batchID 只会生效一次：批次号与点击量在同一事务内写入，已落库的批次直接跳过
+func (d *ProductDao) BatchIncrementClickNum(batchID string, counts map[uint]int64) error {
+	const batchSize = 500
+
+	productIDs := make([]uint, 0, len(counts))
+	for id := range counts {
+		productIDs = append(productIDs, id)
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.ViewFlushBatch{BatchID: batchID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // 该批次已落库（上一轮 Ack 失败）
+		}
+		// 批次号只用于短期去重，顺带清理一周前的记录
+		if err := tx.Where("created_at < ?", time.Now().AddDate(0, 0, -7)).
+			Delete(&model.ViewFlushBatch{}).Error; err != nil {
+			return err
+		}
+
+		for start := 0; start < len(productIDs); start += batchSize {
+			end := start + batchSize
+			if end > len(productIDs) {
+				end = len(productIDs)
+			}
+			batch := productIDs[start:end]
+
+			// UPDATE products SET click_num = click_num + CASE id WHEN ? THEN ? ... END WHERE id IN (?)
+			caseSQL := "CASE id"
+			args := make([]interface{}, 0, len(batch)*2)
+			for _, id := range batch {
+				caseSQL += " WHEN ? THEN ?"
+				args = append(args, id, counts[id])
+			}
+			caseSQL += " ELSE 0 END"
+
+			err := tx.Model(&model.Product{}).Where("id IN (?)", batch).
+				UpdateColumn("click_num", gorm.Expr("click_num + "+caseSQL, args...)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 4. 增减商品销量（订单支付 / 退款时在同一事务内调用，delta 为负数表示扣减）
+func (d *ProductDao) IncrementSalesNum(tx *gorm.DB, productID uint, delta int) error {
+	return tx.Model(&model.Product{}).Where("id = ?", productID).
+		UpdateColumn("num", gorm.Expr("GREATEST(num + ?, 0)", delta)).Error
+}
+
+// 按已支付订单重算全部商品销量（数据修复用）
+// 已退款订单 pay_status = 2，不计入销量
+func (d *ProductDao) RebuildSalesNum() (int64, error) {
+	result := DB.Exec(`
+		UPDATE products p
+		LEFT JOIN (
+			SELECT oi.product_id, SUM(oi.num) AS sales
+			FROM order_items oi
+			JOIN orders o ON o.order_num = oi.order_num
+			WHERE o.pay_status = 1 AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+			GROUP BY oi.product_id
+		) t ON t.product_id = p.id
+		SET p.num = COALESCE(t.sales, 0)
+		WHERE p.deleted_at IS NULL`)
+	return result.RowsAffected, result.Error
 }
 
 // ============ SKU 相关查询 ============
@@ -112,33 +183,76 @@ func (d *ProductDao) GetSkusByIDs(skuIDs []uint) (skus []*model.ProductSku, err
 	return
 }
 
-// ============ 库存扣减（乐观锁） ============
+// 批量查询 SKU 低库存告警阈值
+func (d *ProductDao) GetSkuThresholds(skuIDs []uint) (map[uint]int, error) {
+	var skus []*model.ProductSku
+	err := DB.Model(&model.ProductSku{}).Select("id", "low_stock_threshold").
+		Where("id IN (?)", skuIDs).Find(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+	thresholds := make(map[uint]int, len(skus))
+	for _, sku := range skus {
+		thresholds[sku.ID] = sku.LowStockThreshold
+	}
+	return thresholds, nil
+}
 
-// 8. 扣减库存（乐观锁版本）- 关键！
-func (d *ProductDao) DecrementStock(tx *gorm.DB, skuID uint, quantity int, version int) (rowsAffected int64, err error) {
-	// 正确的乐观锁扣减逻辑
-	result := tx.Model(&model.ProductSku{}).
-		Where("id = ? AND version = ? AND stock >= ?", skuID, version, quantity).
-		// ↑ SKU ID     ↑ 版本号      ↑ 库存充足
-		Updates(map[string]interface{}{
-			"stock":   gorm.Expr("stock - ?", quantity),
-			"version": gorm.Expr("version + ?", 1),
-		})
-
-		// 如果 RowsAffected = 0，说明：
-		// - version 不匹配（被其他请求抢先更新）
-		// - stock 不足
-		// 需要在 Service 层重试或返回失败
-	return result.RowsAffected, result.Error
+// SkuStockVersion SKU 可售库存及其版本号（库存 / 预占的每次变更都会递增版本号）
+type SkuStockVersion struct {
+	Available int
+	Version   int
 }
 
-// 9. 回退库存（取消订单/支付超时）
-func (d *ProductDao) IncrementStock(skuID uint, quantity int) error {
-	return DB.Model(&model.ProductSku{}).Where("id = ?", skuID).
-		Updates(map[string]interface{}{
-			"stock":   gorm.Expr("stock + ?", quantity),
-			"version": gorm.Expr("version + ?", 1),
-		}).Error
+// 批量查询 SKU 可售库存及版本号（库存镜像校正用）
+func (d *ProductDao) GetSkuStockVersions(skuIDs []uint) (map[uint]SkuStockVersion, error) {
+	var skus []*model.ProductSku
+	err := DB.Model(&model.ProductSku{}).Select("id", "stock", "reserved", "version").
+		Where("id IN (?)", skuIDs).Find(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[uint]SkuStockVersion, len(skus))
+	for _, sku := range skus {
+		versions[sku.ID] = SkuStockVersion{Available: sku.Stock - sku.Reserved, Version: sku.Version}
+	}
+	return versions, nil
+}
+
+// 批量查询 SKU 可售库存（库存 - 预占，库存镜像回源用）
+func (d *ProductDao) GetSkuStocksByIDs(skuIDs []uint) (map[uint]int, error) {
+	var skus []*model.ProductSku
+	err := DB.Model(&model.ProductSku{}).Select("id", "stock", "reserved").
+		Where("id IN (?)", skuIDs).Find(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+	stocks := make(map[uint]int, len(skus))
+	for _, sku := range skus {
+		stocks[sku.ID] = sku.Stock - sku.Reserved
+	}
+	return stocks, nil
+}
+
+// 按 ID 游标分批扫描 SKU ID（库存镜像校正用）
+func (d *ProductDao) ScanSkuIDs(afterID uint, limit int) (skuIDs []uint, err error) {
+	err = DB.Model(&model.ProductSku{}).Where("id > ?", afterID).
+		Order("id ASC").Limit(limit).Pluck("id", &skuIDs).Error
+	return
+}
+
+// ============ 库存回补 ============
+
+// 8. 回补库存（退款 / 无预占记录的旧订单取消，事务内调用并记录库存流水）
+func (d *ProductDao) IncrementStock(tx *gorm.DB, skuID uint, quantity int, reason, orderNum, operator string) error {
+	_, err := Inventory.ChangeStock(tx, StockChange{
+		SkuID:    skuID,
+		Delta:    quantity,
+		Reason:   reason,
+		OrderNum: orderNum,
+		Operator: operator,
+	})
+	return err
 }
 
 // ============ 商品管理（CRUD） ============
@@ -151,22 +265,125 @@ func (d *ProductDao) CreateProductSPU(tx *gorm.DB, product *model.Product) error
 	//     ↑ 使用传入的 tx，而不是全局 DB
 }
 
-// 11. 创建商品SKU（支持事务）
+// 11. 创建商品SKU（支持事务，同时记录初始库存流水）
 func (d *ProductDao) CreateProductSKUs(tx *gorm.DB, skus []*model.ProductSku) error {
-	return tx.Create(skus).Error
+	if err := tx.Create(skus).Error; err != nil {
+		return err
+	}
+	return Inventory.RecordInitialStocks(tx, skus)
+}
+
+// 12. 更新 SKU 价格
+func (d *ProductDao) UpdateSkuPrice(skuID uint, price int64) error {
+	return DB.Model(&model.ProductSku{}).Where("id = ?", skuID).Update("price", price).Error
 }
 
-// 12. 更新SKU库存（直接设置库存值）
-func (d *ProductDao) UpdateSkuStock(skuID uint, stock int) error {
-	return DB.Model(&model.ProductSku{}).
-		Where("id = ?", skuID).
+// 12.1 更新商品展示价格和折扣价
+func (d *ProductDao) UpdateProductPrice(productID uint, price, discountPrice int64) error {
+	return DB.Model(&model.Product{}).Where("id = ?", productID).
 		Updates(map[string]interface{}{
-			"stock":   stock,
-			"version": gorm.Expr("version + ?", 1),
+			"price":          price,
+			"discount_price": discountPrice,
 		}).Error
 }
 
+// 12.2 修改 SKU 的下单预占方式（乐观锁 / 条件扣减）
+func (d *ProductDao) UpdateSkuStockMode(skuID uint, mode int8) error {
+	return DB.Model(&model.ProductSku{}).Where("id = ?", skuID).Update("stock_mode", mode).Error
+}
+
+// 12.3 设置 SKU 低库存告警阈值
+func (d *ProductDao) UpdateSkuLowStockThreshold(skuID uint, threshold int) error {
+	return DB.Model(&model.ProductSku{}).Where("id = ?", skuID).Update("low_stock_threshold", threshold).Error
+}
+
+// 批量查询商品（key 为商品 ID）
+func (d *ProductDao) GetProductsByIDs(productIDs []uint) (map[uint]*model.Product, error) {
+	var products []*model.Product
+	if err := DB.Model(&model.Product{}).Where("id IN (?)", productIDs).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]*model.Product, len(products))
+	for _, product := range products {
+		result[product.ID] = product
+	}
+	return result, nil
+}
+
+// 同分类热销商品（按销量倒序，关联推荐兜底用）
+func (d *ProductDao) GetCategoryBestSellers(categoryID uint, excludeIDs []uint, limit int) (products []*model.Product, err error) {
+	query := DB.Model(&model.Product{}).Where("category_id = ? AND on_sale = ?", categoryID, true)
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN (?)", excludeIDs)
+	}
+	err = query.Order("num DESC, id DESC").Limit(limit).Find(&products).Error
+	return
+}
+
 // 13. 更新商品状态
 func (d *ProductDao) UpdateProductOnSale(productID uint, onSale bool) error {
 	return DB.Model(&model.Product{}).Where("id=?", productID).Update("on_sale", onSale).Error
 }
+
+// 13.1 设置定时上下架时间（nil 表示取消）
+func (d *ProductDao) UpdateSaleSchedule(productID uint, onSaleAt, offSaleAt *time.Time) error {
+	return DB.Model(&model.Product{}).Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"on_sale_at":  onSaleAt,
+			"off_sale_at": offSaleAt,
+		}).Error
+}
+
+// 13.2 执行到期的定时上下架，返回上架和下架的商品 ID
+// 执行后清空对应的定时时间，之后手动上下架不会被覆盖
+func (d *ProductDao) ApplySaleSchedules(now time.Time) (listed, delisted []uint, err error) {
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		// 1. 到点下架（同时到点时以下架为准）
+		if err := tx.Model(&model.Product{}).
+			Where("off_sale_at IS NOT NULL AND off_sale_at <= ?", now).
+			Pluck("id", &delisted).Error; err != nil {
+			return err
+		}
+		if len(delisted) > 0 {
+			if err := tx.Model(&model.Product{}).Where("id IN (?)", delisted).
+				Updates(map[string]interface{}{
+					"on_sale":     false,
+					"off_sale_at": nil,
+					"on_sale_at":  gorm.Expr("CASE WHEN on_sale_at <= ? THEN NULL ELSE on_sale_at END", now),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. 到点上架
+		if err := tx.Model(&model.Product{}).
+			Where("on_sale_at IS NOT NULL AND on_sale_at <= ?", now).
+			Pluck("id", &listed).Error; err != nil {
+			return err
+		}
+		if len(listed) > 0 {
+			return tx.Model(&model.Product{}).Where("id IN (?)", listed).
+				Updates(map[string]interface{}{
+					"on_sale":    true,
+					"on_sale_at": nil,
+				}).Error
+		}
+		return nil
+	})
+	return
+}
+
+// ============ 热点商品（Redis） ============
+
+const hotProductKey = "product:hot"
+
+// 14. 标记热点商品（详情缓存使用逻辑过期模式）
+func (d *ProductDao) MarkHotProduct(ctx context.Context, productID uint) error {
+	return Rdb.SAdd(ctx, hotProductKey, productID).Err()
+}
+
+// 15. 判断是否热点商品
+func (d *ProductDao) IsHotProduct(ctx context.Context, productID uint) bool {
+	isHot, err := Rdb.SIsMember(ctx, hotProductKey, productID).Result()
+	return err == nil && isHot
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============ 批量导入 / 导出 ============
+
+// GetSkusByCodes 按商家编码批量查询并锁定 SKU（key 为编码，导入覆盖库存时流水的变化量以锁定时的库存为准）
+func (d *ProductDao) GetSkusByCodes(tx *gorm.DB, codes []string) (map[string]*model.ProductSku, error) {
+	result := make(map[string]*model.ProductSku, len(codes))
+	if len(codes) == 0 {
+		return result, nil
+	}
+	var skus []*model.ProductSku
+	if err := tx.Model(&model.ProductSku{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code IN (?)", codes).Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	for _, sku := range skus {
+		result[sku.Code] = sku
+	}
+	return result, nil
+}
+
+// GetProductBySpuCode 按商家 SPU 编码查询商品（不存在时返回 nil）
+func (d *ProductDao) GetProductBySpuCode(tx *gorm.DB, spuCode string) (*model.Product, error) {
+	var products []*model.Product
+	if err := tx.Model(&model.Product{}).Where("spu_code = ?", spuCode).Limit(1).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, nil
+	}
+	return products[0], nil
+}
+
+// SaveProduct 更新商品的全部可导入字段
+func (d *ProductDao) SaveProduct(tx *gorm.DB, product *model.Product) error {
+	return tx.Model(&model.Product{}).Where("id = ?", product.ID).
+		Updates(map[string]interface{}{
+			"spu_code":       product.SpuCode,
+			"name":           product.Name,
+			"category_id":    product.CategoryID,
+			"title":          product.Title,
+			"info":           product.Info,
+			"img_path":       product.ImgPath,
+			"price":          product.Price,
+			"discount_price": product.DiscountPrice,
+			"on_sale":        product.OnSale,
+		}).Error
+}
+
+// SaveSku 更新 SKU 的可导入字段（库存变化时版本号 +1，使进行中的乐观锁扣减重试，并记录导入流水）
+func (d *ProductDao) SaveSku(tx *gorm.DB, sku *model.ProductSku, oldStock int, remark string) error {
+	updates := map[string]interface{}{
+		"title": sku.Title,
+		"price": sku.Price,
+		"stock": sku.Stock,
+	}
+	if sku.Stock != oldStock {
+		updates["version"] = gorm.Expr("version + ?", 1)
+	}
+	if err := tx.Model(&model.ProductSku{}).Where("id = ?", sku.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+	if sku.Stock == oldStock {
+		return nil
+	}
+	_, err := Inventory.RecordMovement(tx, StockChange{
+		SkuID:  sku.ID,
+		Delta:  sku.Stock - oldStock,
+		Reason: constants.STOCK_REASON_IMPORT,
+		Remark: remark,
+	})
+	return err
+}
+
+// ScanProducts 按 ID 升序分批扫描商品（导出用，支持与商品列表相同的筛选条件）
+func (d *ProductDao) ScanProducts(categoryID uint, keyword string, onSale *bool, afterID uint, limit int) (products []*model.Product, err error) {
+	query := DB.Model(&model.Product{}).Where("id > ?", afterID)
+	if categoryID > 0 {
+		query = query.Where("category_id = ?", categoryID)
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if onSale != nil {
+		query = query.Where("on_sale = ?", *onSale)
+	}
+	err = query.Order("id ASC").Limit(limit).Find(&products).Error
+	return
+}
+
+// GetSkusByProductIDs 批量查询多个商品的 SKU（key 为商品 ID，SKU 按 ID 升序）
+func (d *ProductDao) GetSkusByProductIDs(productIDs []uint) (map[uint][]*model.ProductSku, error) {
+	result := make(map[uint][]*model.ProductSku, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+	var skus []*model.ProductSku
+	if err := DB.Model(&model.ProductSku{}).Where("product_id IN (?)", productIDs).Order("id ASC").Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	for _, sku := range skus {
+		result[sku.ProductID] = append(result[sku.ProductID], sku)
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"xiaomi-mall/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============ 商品图集 / 图文详情 ============
+
+// ReplaceImages 整体替换商品（skuID = 0）或 SKU 的图集
+func (d *ProductDao) ReplaceImages(tx *gorm.DB, productID, skuID uint, urls []string) error {
+	// 1. 删除旧图集
+	if err := tx.Where("product_id = ? AND product_sku_id = ?", productID, skuID).
+		Delete(&model.ProductImage{}).Error; err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	// 2. 写入新图集（按传入顺序排序）
+	images := make([]*model.ProductImage, 0, len(urls))
+	for i, url := range urls {
+		images = append(images, &model.ProductImage{
+			ProductID:    productID,
+			ProductSkuID: skuID,
+			URL:          url,
+			Sort:         i,
+		})
+	}
+	return tx.Create(images).Error
+}
+
+// UpdateCover 更新商品（skuID = 0）或 SKU 的封面图（列表只读封面字段）
+func (d *ProductDao) UpdateCover(tx *gorm.DB, productID, skuID uint, url string) error {
+	if skuID == 0 {
+		return tx.Model(&model.Product{}).Where("id = ?", productID).Update("img_path", url).Error
+	}
+	return tx.Model(&model.ProductSku{}).Where("id = ? AND product_id = ?", skuID, productID).
+		Update("img_path", url).Error
+}
+
+// GetImages 查询商品的全部图集（key 为 SKU ID，0 为商品主图集）
+func (d *ProductDao) GetImages(productID uint) (map[uint][]string, error) {
+	var images []*model.ProductImage
+	err := DB.Model(&model.ProductImage{}).
+		Where("product_id = ?", productID).
+		Order("product_sku_id ASC, sort ASC").
+		Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]string)
+	for _, image := range images {
+		result[image.ProductSkuID] = append(result[image.ProductSkuID], image.URL)
+	}
+	return result, nil
+}
+
+// SaveContent 保存商品图文详情（不存在则创建）
+func (d *ProductDao) SaveContent(tx *gorm.DB, productID uint, blocks string) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"blocks", "updated_at"}),
+	}).Create(&model.ProductContent{ProductID: productID, Blocks: blocks}).Error
+}
+
+// GetContent 查询商品图文详情（未设置时返回空字符串）
+func (d *ProductDao) GetContent(productID uint) (string, error) {
+	var content model.ProductContent
+	err := DB.Model(&model.ProductContent{}).Where("product_id = ?", productID).Limit(1).Find(&content).Error
+	return content.Blocks, err
+}
@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"xiaomi-mall/internal/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ============ 商品关联推荐（Redis ZSET） ============
+// product:copurchase:{product_id}  ZSET  member = 一起购买过的商品 ID，score = 共同购买次数
+// 每个商品只保留 score 最高的 RelatedTopN 个（增量累加时同样裁剪，长尾计数会丢失，由离线任务定期全量重建）
+
+var Recommend = new(RecommendDao)
+
+type RecommendDao struct{}
+
+const RelatedTopN = 50
+
+func coPurchaseKey(productID uint) string {
+	return fmt.Sprintf("product:copurchase:%d", productID)
+}
+
+// 1. 全量替换某商品的关联商品（先写临时 key 再 RENAME，读取方不会看到半成品）
+func (d *RecommendDao) ReplaceRelated(ctx context.Context, productID uint, scores map[uint]int64) error {
+	key := coPurchaseKey(productID)
+	if len(scores) == 0 {
+		return Rdb.Del(ctx, key).Err()
+	}
+
+	tmpKey := key + ":tmp"
+	members := make([]*redis.Z, 0, len(scores))
+	for relatedID, score := range scores {
+		members = append(members, &redis.Z{Score: float64(score), Member: relatedID})
+	}
+
+	pipe := Rdb.TxPipeline()
+	pipe.Del(ctx, tmpKey)
+	pipe.ZAdd(ctx, tmpKey, members...)
+	pipe.ZRemRangeByRank(ctx, tmpKey, 0, -RelatedTopN-1)
+	pipe.Rename(ctx, tmpKey, key)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 2. 增量累加一笔订单中商品两两之间的共同购买次数
+func (d *RecommendDao) IncrCoPurchase(ctx context.Context, productIDs []uint) error {
+	if len(productIDs) < 2 {
+		return nil
+	}
+
+	pipe := Rdb.Pipeline()
+	for _, a := range productIDs {
+		key := coPurchaseKey(a)
+		for _, b := range productIDs {
+			if a == b {
+				continue
+			}
+			pipe.ZIncrBy(ctx, key, 1, strconv.FormatUint(uint64(b), 10))
+		}
+		pipe.ZRemRangeByRank(ctx, key, 0, -RelatedTopN-1)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 3. 查询关联商品 ID（按共同购买次数倒序）
+func (d *RecommendDao) GetRelated(ctx context.Context, productID uint, limit int) ([]uint, error) {
+	members, err := Rdb.ZRevRange(ctx, coPurchaseKey(productID), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// ============ 离线计算数据源（MySQL） ============
+
+// 4. 按 ID 游标分批扫描已支付订单（只取 id、order_num）
+func (d *RecommendDao) ScanPaidOrders(afterID uint, limit int) (orders []*model.Order, err error) {
+	err = DB.Model(&model.Order{}).Select("id", "order_num").
+		Where("id > ? AND pay_status = ?", afterID, 1).
+		Order("id ASC").Limit(limit).Find(&orders).Error
+	return
+}
+
+// 5. 批量查询订单商品（只取 order_num、product_id）
+func (d *RecommendDao) GetOrderProducts(orderNums []string) (items []*model.OrderItem, err error) {
+	err = DB.Model(&model.OrderItem{}).Select("order_num", "product_id").
+		Where("order_num IN (?)", orderNums).Find(&items).Error
+	return
+}
@@ -25,4 +25,6 @@ func InitRedis() {
 	}
 
 	fmt.Println("✅ Redis 连接成功！") // ⬅️ 加上这行
+
+	initOrderQueues()
 }
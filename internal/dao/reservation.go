@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"time"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============ 库存预占 ============
+// 下单只增加 product_skus.reserved（可售库存 = stock - reserved），支付时才真正扣减 stock；
+// 取消 / 超时释放预占。预占记录带到期时间，即使延迟队列丢失，清扫任务也能兜底释放
+
+var Reservation = new(ReservationDao)
+
+type ReservationDao struct{}
+
+// ReserveResult 预占结果
+type ReserveResult int
+
+const (
+	ReserveOK         ReserveResult = iota
+	ReserveConflict                 // 乐观锁版本冲突（可售库存充足，但被其他请求抢先更新）
+	ReserveOutOfStock               // 可售库存不足
+)
+
+// 1. 预占库存（事务内调用）
+// 乐观锁模式校验读取 SKU 时的版本号，冲突时需要在 Service 层重试；条件扣减模式只校验可售库存
+func (d *ReservationDao) Reserve(tx *gorm.DB, reservation *model.StockReservation, sku *model.ProductSku) (ReserveResult, error) {
+	query := tx.Model(&model.ProductSku{}).
+		Where("id = ? AND stock - reserved >= ?", sku.ID, reservation.Quantity)
+	if sku.StockMode == constants.STOCK_MODE_OPTIMISTIC {
+		query = query.Where("version = ?", sku.Version)
+	}
+	result := query.Updates(map[string]interface{}{
+		"reserved": gorm.Expr("reserved + ?", reservation.Quantity),
+		"version":  gorm.Expr("version + ?", 1),
+	})
+	if result.Error != nil {
+		return ReserveOutOfStock, result.Error
+	}
+
+	// 更新失败：乐观锁模式下再读一次可售库存，区分版本冲突和库存不足
+	if result.RowsAffected == 0 {
+		if sku.StockMode != constants.STOCK_MODE_OPTIMISTIC {
+			return ReserveOutOfStock, nil
+		}
+		var available int
+		if err := tx.Model(&model.ProductSku{}).Where("id = ?", sku.ID).
+			Select("stock - reserved").Scan(&available).Error; err != nil {
+			return ReserveOutOfStock, err
+		}
+		if available >= reservation.Quantity {
+			return ReserveConflict, nil
+		}
+		return ReserveOutOfStock, nil
+	}
+
+	reservation.Status = constants.RESERVATION_STATUS_ACTIVE
+	return ReserveOK, tx.Create(reservation).Error
+}
+
+// 2. 查询订单的全部预占记录（并锁定，旧订单没有预占记录）
+func (d *ReservationDao) GetByOrder(tx *gorm.DB, orderNum string) (reservations []*model.StockReservation, err error) {
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_num = ?", orderNum).Find(&reservations).Error
+	return
+}
+
+// 3. 支付成功：预占转为实际扣减并记录库存流水（只处理预占中的记录，重复调用无副作用）
+func (d *ReservationDao) Convert(tx *gorm.DB, orderNum, operator string) ([]*model.StockReservation, error) {
+	return d.settle(tx, orderNum, constants.RESERVATION_STATUS_CONVERTED, func(r *model.StockReservation) error {
+		if err := tx.Model(&model.ProductSku{}).Where("id = ?", r.ProductSkuID).
+			Updates(map[string]interface{}{
+				"stock":    gorm.Expr("stock - ?", r.Quantity),
+				"reserved": gorm.Expr("reserved - ?", r.Quantity),
+				"version":  gorm.Expr("version + ?", 1),
+			}).Error; err != nil {
+			return err
+		}
+		_, err := Inventory.RecordMovement(tx, StockChange{
+			SkuID:    r.ProductSkuID,
+			Delta:    -r.Quantity,
+			Reason:   constants.STOCK_REASON_ORDER,
+			OrderNum: orderNum,
+			Operator: operator,
+		})
+		return err
+	})
+}
+
+// 4. 取消 / 超时：释放预占（库存未扣减，不产生库存流水；只处理预占中的记录，重复调用无副作用）
+func (d *ReservationDao) Release(tx *gorm.DB, orderNum string) ([]*model.StockReservation, error) {
+	return d.settle(tx, orderNum, constants.RESERVATION_STATUS_RELEASED, func(r *model.StockReservation) error {
+		return tx.Model(&model.ProductSku{}).Where("id = ?", r.ProductSkuID).
+			Updates(map[string]interface{}{
+				"reserved": gorm.Expr("reserved - ?", r.Quantity),
+				"version":  gorm.Expr("version + ?", 1),
+			}).Error
+	})
+}
+
+// settle 将订单预占中的记录改为 status，并对每条实际变更的记录执行 apply，返回被处理的记录
+func (d *ReservationDao) settle(tx *gorm.DB, orderNum string, status int8, apply func(*model.StockReservation) error) ([]*model.StockReservation, error) {
+	reservations, err := d.GetByOrder(tx, orderNum)
+	if err != nil {
+		return nil, err
+	}
+	settled := make([]*model.StockReservation, 0, len(reservations))
+	for _, r := range reservations {
+		if r.Status != constants.RESERVATION_STATUS_ACTIVE {
+			continue
+		}
+		result := tx.Model(&model.StockReservation{}).
+			Where("id = ? AND status = ?", r.ID, constants.RESERVATION_STATUS_ACTIVE).
+			Update("status", status)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := apply(r); err != nil {
+			return nil, err
+		}
+		settled = append(settled, r)
+	}
+	return settled, nil
+}
+
+// 5. 查询到期仍在预占中的订单号（清扫任务用）
+func (d *ReservationDao) GetExpiredOrderNums(before time.Time, limit int) (orderNums []string, err error) {
+	err = DB.Model(&model.StockReservation{}).
+		Where("status = ? AND expire_time <= ?", constants.RESERVATION_STATUS_ACTIVE, before).
+		Distinct("order_num").Limit(limit).Pluck("order_num", &orderNums).Error
+	return
+}
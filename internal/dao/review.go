@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"time"
+	"xiaomi-mall/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var Review = new(ReviewDao)
+
+type ReviewDao struct{}
+
+// ========== 创建评价（事务）==========
+func (d *ReviewDao) CreateReview(tx *gorm.DB, review *model.Review) error {
+	return tx.Create(review).Error
+}
+
+// ========== 根据 ID 查询评价 ==========
+func (d *ReviewDao) GetByID(reviewID uint) (*model.Review, error) {
+	var review model.Review
+	err := DB.Where("id = ?", reviewID).First(&review).Error
+	return &review, err
+}
+
+// ========== 订单商品是否已评价 ==========
+func (d *ReviewDao) ExistByOrderItemID(orderItemID uint) (bool, error) {
+	var count int64
+	err := DB.Model(&model.Review{}).Where("order_item_id = ?", orderItemID).Count(&count).Error
+	return count > 0, err
+}
+
+// ========== 查询订单中已评价的订单商品 ID ==========
+func (d *ReviewDao) GetReviewedItemIDs(orderNum string) (map[uint]bool, error) {
+	var itemIDs []uint
+	err := DB.Model(&model.Review{}).Where("order_num = ?", orderNum).
+		Pluck("order_item_id", &itemIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	reviewed := make(map[uint]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		reviewed[id] = true
+	}
+	return reviewed, nil
+}
+
+// ========== 商品评价列表（分页 + 星级/有图筛选）==========
+func (d *ReviewDao) GetProductReviews(productID uint, star int, hasImage bool, page, pageSize int) ([]*model.Review, int64, error) {
+	var reviews []*model.Review
+	var total int64
+
+	query := DB.Model(&model.Review{}).Where("product_id = ?", productID)
+	if star > 0 {
+		query = query.Where("star = ?", star)
+	}
+	if hasImage {
+		query = query.Where("has_image = ?", true)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// ========== 追评（只能追评一次）==========
+func (d *ReviewDao) FollowUp(reviewID, userID uint, content, images string) (int64, error) {
+	now := time.Now()
+	result := DB.Model(&model.Review{}).
+		Where("id = ? AND user_id = ? AND follow_up_time IS NULL", reviewID, userID).
+		Updates(map[string]interface{}{
+			"follow_up_content": content,
+			"follow_up_images":  images,
+			"follow_up_time":    &now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ========== 商家回复（可修改）==========
+func (d *ReviewDao) Reply(reviewID uint, reply string) (int64, error) {
+	now := time.Now()
+	result := DB.Model(&model.Review{}).Where("id = ?", reviewID).
+		Updates(map[string]interface{}{
+			"reply":      reply,
+			"reply_time": &now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ============ 评分汇总 ============
+
+// 增量更新评分汇总（不存在则创建）
+func (d *ReviewDao) IncrRatingStat(tx *gorm.DB, productID, skuID uint, star int) error {
+	stat := &model.RatingStat{
+		ProductID:    productID,
+		ProductSkuID: skuID,
+		ReviewCount:  1,
+		StarSum:      int64(star),
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "product_sku_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"review_count": gorm.Expr("review_count + 1"),
+			"star_sum":     gorm.Expr("star_sum + ?", star),
+			"updated_at":   time.Now(),
+		}),
+	}).Create(stat).Error
+}
+
+// 查询商品的评分汇总（key 为 SKU ID，0 表示整个商品）
+func (d *ReviewDao) GetRatingStats(productID uint) (map[uint]*model.RatingStat, error) {
+	var stats []*model.RatingStat
+	if err := DB.Where("product_id = ?", productID).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]*model.RatingStat, len(stats))
+	for _, stat := range stats {
+		result[stat.ProductSkuID] = stat
+	}
+	return result, nil
+}
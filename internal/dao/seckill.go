@@ -7,8 +7,11 @@ import (
 	"strconv"
 	"time"
 	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
 
 	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SeckillDao struct{}
@@ -17,16 +20,80 @@ var Seckill = new(SeckillDao)
 
 // ==================== 管理端：秒杀商品管理 ====================
 
-// 创建秒杀商品入库
-func (d *SeckillDao) CreateSeckillProduct(product *model.SeckillProduct) error {
-	return DB.Create(product).Error
+// 创建秒杀商品入库（支持事务，与 SKU 库存预留一起提交）
+func (d *SeckillDao) CreateSeckillProduct(tx *gorm.DB, product *model.SeckillProduct) error {
+	return tx.Create(product).Error
 
 }
 
-// 删除秒杀商品
-func (d *SeckillDao) DeleteSeckillProduct(id uint) error {
+// 删除秒杀商品（支持事务，与归还预留库存一起提交）
+func (d *SeckillDao) DeleteSeckillProduct(tx *gorm.DB, id uint) (int64, error) {
 	// return DB.Model(&model.SeckillProduct{}).Where("id =?", id).Delete(&model.SeckillProduct{}).Error
-	return DB.Delete(&model.SeckillProduct{}, "id = ?", id).Error
+	result := tx.Delete(&model.SeckillProduct{}, "id = ?", id)
+	return result.RowsAffected, result.Error
+}
+
+// 统计占用预留库存的秒杀订单数（待支付 + 已支付）
+func (d *SeckillDao) CountHoldingSeckillOrders(tx *gorm.DB, seckillID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&model.SeckillOrder{}).
+		Where("seckill_product_id = ? AND status IN (?)", seckillID, []int8{0, 1}).
+		Count(&count).Error
+	return count, err
+}
+
+// 归还秒杀订单占用的库存（关单、退款事务内调用，放在事务最后一步）
+// 活动仍在售时回补 Redis 秒杀库存；活动已删除或已停售时这件库存不会再被秒杀卖出，
+// 直接归还到 SKU（活动有预留流水时才归还）。返回归还到的 SKU ID，0 表示没有归还到 SKU
+func (d *SeckillDao) ReturnSeckillOrderStock(ctx context.Context, tx *gorm.DB, seckillID uint, orderNum, operator string) (uint, error) {
+	// 1. 锁住活动（含已删除），与删除活动的事务串行
+	var product model.SeckillProduct
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", seckillID).First(&product).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. 活动未删除：库存 key 存在时回补；已停售（删除中）时归还到 SKU；都不存在时留在活动预留中，删除时按订单占用数归还
+	if !product.DeletedAt.Valid {
+		script := `
+			if redis.call('EXISTS', KEYS[1]) == 1 then
+				redis.call('INCR', KEYS[1])
+				return 1
+			end
+			if redis.call('EXISTS', KEYS[2]) == 1 then
+				return 2
+			end
+			return 0
+		`
+		keys := []string{fmt.Sprintf("seckill:stock:%d", seckillID), fmt.Sprintf("seckill:stopped:%d", seckillID)}
+		result, err := Rdb.Eval(ctx, script, keys).Int()
+		if err != nil || result != 2 {
+			return 0, err
+		}
+	}
+
+	// 3. 归还到 SKU 并记录流水
+	reserved, err := Inventory.HasMovement(tx, SeckillStockRef(seckillID), constants.STOCK_REASON_SECKILL_RESERVE)
+	if err != nil || !reserved {
+		return 0, err
+	}
+	movement, err := Inventory.ChangeStock(tx, StockChange{
+		SkuID:    product.SkuID,
+		Delta:    1, // 秒杀固定1件
+		Reason:   constants.STOCK_REASON_SECKILL_RELEASE,
+		OrderNum: orderNum,
+		Operator: operator,
+	})
+	if err != nil || movement == nil {
+		return 0, err
+	}
+	return product.SkuID, nil
+}
+
+// SeckillStockRef 秒杀活动在库存流水中的关联标识（预留、删除归还）
+func SeckillStockRef(seckillID uint) string {
+	return fmt.Sprintf("seckill:%d", seckillID)
 }
 
 // 手动开启/结束秒杀
@@ -41,6 +108,13 @@ func (d *SeckillDao) GetSeckillProductByID(id uint) (*model.SeckillProduct, erro
 	return &seckillProduct, err
 }
 
+// 查询秒杀商品详情（含已删除，删除前已抢到的订单仍需写入）
+func (d *SeckillDao) GetSeckillProductUnscoped(id uint) (*model.SeckillProduct, error) {
+	var seckillProduct model.SeckillProduct
+	err := DB.Unscoped().Model(&model.SeckillProduct{}).Where("id = ?", id).First(&seckillProduct).Error
+	return &seckillProduct, err
+}
+
 // ==================== 用户端：秒杀商品查询 ====================
 
 // ==================== 用户端：秒杀商品下单 ====================
@@ -283,7 +357,8 @@ func (d *SeckillDao) CheckUserPurchased(ctx context.Context, seckillID, userID u
 }
 
 // 原子性地创建秒杀订单
-func (d *SeckillDao) CreateSeckillOrderAtomic(ctx context.Context, userID, seckillID uint, orderNum string) (int, error) {
+// expireTime 为支付截止时间，随订单数据写入队列，落库时直接使用
+func (d *SeckillDao) CreateSeckillOrderAtomic(ctx context.Context, userID, seckillID uint, orderNum string, expireTime time.Time) (int, error) {
 	script := `
 		-- 原子性创建秒杀订单
 		local stock_key = KEYS[1]
@@ -294,6 +369,7 @@ func (d *SeckillDao) CreateSeckillOrderAtomic(ctx context.Context, userID, secki
 		local seckill_id = ARGV[2]
 		local order_num = ARGV[3]
 		local ttl = ARGV[4]
+		local expire_time = tonumber(ARGV[5])
 
 		-- 1. 检查用户是否购买
 		if redis.call('EXISTS', user_key) == 1 then
@@ -324,6 +400,7 @@ func (d *SeckillDao) CreateSeckillOrderAtomic(ctx context.Context, userID, secki
 			seckill_id = seckill_id,
 			order_num = order_num,
 			timestamp = timestamp,
+			expire_time = expire_time,
 			retry_count = 0,
 			first_try_time = timestamp,
 			last_try_time = timestamp,
@@ -341,6 +418,7 @@ func (d *SeckillDao) CreateSeckillOrderAtomic(ctx context.Context, userID, secki
 		seckillID,
 		orderNum,
 		86400,
+		expireTime.Unix(),
 	).Int()
 	if err != nil {
 		return 0, err
@@ -369,6 +447,45 @@ func (d *SeckillDao) AddToActiveList(ctx context.Context, seckillID uint, startT
 	return err
 }
 
+// 停售标记的保留时间（记录停售时的剩余库存，删除失败重试时沿用；期间关闭的订单直接归还到 SKU）
+const seckillStoppedTTL = 30 * 24 * time.Hour
+
+// StopSeckillSale 停止售卖：原子地移出活动列表、取走 Redis 剩余库存并删除商品缓存
+// 返回 Redis 中剩余的库存；preheated=false 表示库存 key 不存在（未预热或已过期）
+// 取走库存时写入停售标记，重复停售（删除失败重试）时返回第一次停售时的剩余库存
+func (d *SeckillDao) StopSeckillSale(ctx context.Context, seckillID uint) (remaining int, preheated bool, err error) {
+	script := `
+		redis.call('ZREM', KEYS[1], ARGV[1])
+		redis.call('ZREM', KEYS[2], ARGV[1])
+		local stock = redis.call('GET', KEYS[3])
+		redis.call('DEL', KEYS[3], KEYS[4])
+		if not stock then
+			stock = redis.call('GET', KEYS[5])
+			if not stock then
+				return -1
+			end
+			return tonumber(stock)
+		end
+		redis.call('SET', KEYS[5], stock, 'EX', ARGV[2])
+		return tonumber(stock)
+	`
+	keys := []string{
+		"seckill:active:start",
+		"seckill:active:end",
+		fmt.Sprintf("seckill:stock:%d", seckillID),
+		fmt.Sprintf("seckill:product:%d", seckillID),
+		fmt.Sprintf("seckill:stopped:%d", seckillID),
+	}
+	stock, err := Rdb.Eval(ctx, script, keys, seckillID, int64(seckillStoppedTTL.Seconds())).Int()
+	if err != nil {
+		return 0, false, err
+	}
+	if stock < 0 {
+		return 0, false, nil
+	}
+	return stock, true, nil
+}
+
 // RemoveFromActiveList 从活动列表中移除秒杀商品
 func (d *SeckillDao) RemoveFromActiveList(ctx context.Context, seckillID uint) error {
 	startList := "seckill:active:start"
@@ -0,0 +1,143 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ============ SKU 实时库存镜像（Redis） ============
+// MySQL product_skus 是唯一真实来源，Redis 镜像保存可售库存（stock - reserved），随 MySQL 预占/释放/调整同步更新，
+// 供商品详情、SKU 详情读取实时库存；定时任务负责兜底校正
+// 每次写入镜像都会递增该 SKU 的序号（sku:stock:seq:<id>），校正时按序号 CAS，避免覆盖掉并发的同步
+
+var Stock = new(StockDao)
+
+type StockDao struct{}
+
+func skuStockKey(skuID uint) string {
+	return fmt.Sprintf("sku:stock:%d", skuID)
+}
+
+func skuStockSeqKey(skuID uint) string {
+	return fmt.Sprintf("sku:stock:seq:%d", skuID)
+}
+
+// SkuStockSnapshot 库存镜像及其写入序号
+type SkuStockSnapshot struct {
+	Stock int
+	Seq   int64
+}
+
+// 1. 设置库存镜像（初始化 / 管理员直接设置库存 / 回源）
+func (d *StockDao) SetSkuStock(ctx context.Context, skuID uint, stock int) error {
+	pipe := Rdb.TxPipeline()
+	pipe.Set(ctx, skuStockKey(skuID), stock, 0)
+	pipe.Incr(ctx, skuStockSeqKey(skuID))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 2. 增减库存镜像，返回变化后的可售库存（key 不存在时不处理，ok=false，等读取时回源，避免写入错误的初始值）
+func (d *StockDao) IncrSkuStock(ctx context.Context, skuID uint, delta int) (after int, ok bool, err error) {
+	script := `
+		if redis.call('EXISTS', KEYS[1]) == 1 then
+			redis.call('INCR', KEYS[2])
+			return redis.call('INCRBY', KEYS[1], ARGV[1])
+		end
+		return nil
+	`
+	after, err = Rdb.Eval(ctx, script, []string{skuStockKey(skuID), skuStockSeqKey(skuID)}, delta).Int()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return after, true, nil
+}
+
+// 3. 批量读取库存镜像，返回命中的库存和未命中的 SKU ID
+func (d *StockDao) BatchGetSkuStocks(ctx context.Context, skuIDs []uint) (map[uint]int, []uint, error) {
+	stocks := make(map[uint]int, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return stocks, nil, nil
+	}
+
+	keys := make([]string, 0, len(skuIDs))
+	for _, id := range skuIDs {
+		keys = append(keys, skuStockKey(id))
+	}
+
+	values, err := Rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missing := make([]uint, 0)
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			missing = append(missing, skuIDs[i])
+			continue
+		}
+		stock, err := strconv.Atoi(str)
+		if err != nil {
+			missing = append(missing, skuIDs[i])
+			continue
+		}
+		stocks[skuIDs[i]] = stock
+	}
+	return stocks, missing, nil
+}
+
+// 4. 批量读取库存镜像及写入序号（一次 MGET，镜像和序号来自同一时刻；未缓存的 SKU 不返回）
+func (d *StockDao) BatchGetSkuStockSnapshots(ctx context.Context, skuIDs []uint) (map[uint]SkuStockSnapshot, error) {
+	snapshots := make(map[uint]SkuStockSnapshot, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return snapshots, nil
+	}
+
+	keys := make([]string, 0, len(skuIDs)*2)
+	for _, id := range skuIDs {
+		keys = append(keys, skuStockKey(id), skuStockSeqKey(id))
+	}
+	values, err := Rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range skuIDs {
+		str, ok := values[i*2].(string)
+		if !ok {
+			continue
+		}
+		stock, err := strconv.Atoi(str)
+		if err != nil {
+			continue
+		}
+		var seq int64
+		if str, ok := values[i*2+1].(string); ok {
+			seq, _ = strconv.ParseInt(str, 10, 64)
+		}
+		snapshots[id] = SkuStockSnapshot{Stock: stock, Seq: seq}
+	}
+	return snapshots, nil
+}
+
+// 5. 校正库存镜像（CAS：只有写入序号仍是 expectedSeq 时才覆盖，期间有任何同步写入都放弃，留给下一轮）
+func (d *StockDao) CompareAndSetSkuStock(ctx context.Context, skuID uint, expectedSeq int64, stock int) (bool, error) {
+	script := `
+		local seq = tonumber(redis.call('GET', KEYS[2]) or '0')
+		if seq == tonumber(ARGV[1]) then
+			redis.call('SET', KEYS[1], ARGV[2])
+			redis.call('INCR', KEYS[2])
+			return 1
+		end
+		return 0
+	`
+	result, err := Rdb.Eval(ctx, script, []string{skuStockKey(skuID), skuStockSeqKey(skuID)}, expectedSeq, stock).Int()
+	return result == 1, err
+}
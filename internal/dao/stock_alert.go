@@ -0,0 +1,124 @@
+package dao
+
+import (
+	"time"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm/clause"
+)
+
+// ============ 低库存告警 / 到货提醒 ============
+
+var StockAlert = new(StockAlertDao)
+
+type StockAlertDao struct{}
+
+// 1. 打开低库存告警（该 SKU 已有未恢复的告警时不重复创建，返回 false）
+func (d *StockAlertDao) OpenAlert(alert *model.StockAlert) (bool, error) {
+	skuID := alert.ProductSkuID
+	alert.OpenSkuID = &skuID
+	alert.Status = constants.STOCK_ALERT_STATUS_OPEN
+	result := DB.Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// 2. 恢复 SKU 未恢复的告警（可售库存回到阈值以上 / 取消阈值）
+func (d *StockAlertDao) ResolveAlert(skuID uint) error {
+	return DB.Model(&model.StockAlert{}).Where("open_sku_id = ?", skuID).
+		Updates(map[string]interface{}{
+			"open_sku_id": nil,
+			"status":      constants.STOCK_ALERT_STATUS_RESOLVED,
+			"resolved_at": time.Now(),
+		}).Error
+}
+
+// 2.1 查询有未恢复告警的 SKU（避免对没有告警的 SKU 执行恢复）
+func (d *StockAlertDao) GetOpenAlertSkuIDs(skuIDs []uint) (map[uint]bool, error) {
+	var ids []uint
+	err := DB.Model(&model.StockAlert{}).Where("open_sku_id IN (?)", skuIDs).
+		Pluck("open_sku_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		open[id] = true
+	}
+	return open, nil
+}
+
+// 3. 告警记录列表（按时间倒序，可按状态筛选）
+func (d *StockAlertDao) GetAlertList(status *int8, page, pageSize int) ([]*model.StockAlert, int64, error) {
+	var alerts []*model.StockAlert
+	var total int64
+
+	query := DB.Model(&model.StockAlert{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&alerts).Error
+	return alerts, total, err
+}
+
+// 4. 可售库存低于等于阈值的 SKU（按可售库存升序）
+func (d *StockAlertDao) GetLowStockSkus(page, pageSize int) ([]*model.ProductSku, int64, error) {
+	var skus []*model.ProductSku
+	var total int64
+
+	query := DB.Model(&model.ProductSku{}).
+		Where("low_stock_threshold > 0 AND stock - reserved <= low_stock_threshold")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("stock - reserved ASC, id ASC").Limit(pageSize).Offset(offset).Find(&skus).Error
+	return skus, total, err
+}
+
+// 5. 订阅到货提醒（已通知过的订阅重新进入等待状态）
+func (d *StockAlertDao) Subscribe(userID, productID, skuID uint) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "product_sku_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":      constants.STOCK_SUBSCRIPTION_WAITING,
+			"notified_at": nil,
+			"updated_at":  time.Now(),
+		}),
+	}).Create(&model.StockSubscription{
+		UserID:       userID,
+		ProductID:    productID,
+		ProductSkuID: skuID,
+		Status:       constants.STOCK_SUBSCRIPTION_WAITING,
+	}).Error
+}
+
+// 6. 取消到货提醒
+func (d *StockAlertDao) Unsubscribe(userID, skuID uint) error {
+	return DB.Where("user_id = ? AND product_sku_id = ?", userID, skuID).
+		Delete(&model.StockSubscription{}).Error
+}
+
+// 7. 查询 SKU 等待到货的订阅
+func (d *StockAlertDao) GetWaitingSubscriptions(skuID uint) (subscriptions []*model.StockSubscription, err error) {
+	err = DB.Where("product_sku_id = ? AND status = ?", skuID, constants.STOCK_SUBSCRIPTION_WAITING).
+		Find(&subscriptions).Error
+	return
+}
+
+// 8. 标记订阅已通知（并发时只有一个调用返回 true，避免重复通知）
+func (d *StockAlertDao) MarkNotified(id uint) (bool, error) {
+	result := DB.Model(&model.StockSubscription{}).
+		Where("id = ? AND status = ?", id, constants.STOCK_SUBSCRIPTION_WAITING).
+		Updates(map[string]interface{}{
+			"status":      constants.STOCK_SUBSCRIPTION_NOTIFIED,
+			"notified_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -28,3 +28,16 @@ func (d *UserDao) GetUserByPhone(phone string) (user *model.User, err error) {
 	err = DB.Model(&model.User{}).Where("phone = ?", phone).First(&user).Error
 	return
 }
+
+// GetUsersByIDs 批量查询用户（key 为用户 ID）
+func (d *UserDao) GetUsersByIDs(userIDs []uint) (map[uint]*model.User, error) {
+	var users []*model.User
+	if err := DB.Model(&model.User{}).Where("id IN (?)", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]*model.User, len(users))
+	for _, user := range users {
+		result[user.ID] = user
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"xiaomi-mall/pkg/idgen"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ============ 商品浏览统计（Redis） ============
+// product:views:pending         Hash  待落库的点击量增量（product_id -> 次数）
+// product:views:flushing        Hash  正在落库的点击量（落库成功后删除，失败则下一轮重试）
+// product:views:flushing:batch  String 正在落库批次的批次号（MySQL 按批次号去重）
+// product:views:daily:{date}    Hash  每日浏览量（product_id -> 次数）
+// product:uv:{date}:{product_id} HyperLogLog 每日独立访客
+
+var View = new(ViewDao)
+
+type ViewDao struct{}
+
+const (
+	pendingViewsKey  = "product:views:pending"
+	flushingViewsKey = "product:views:flushing"
+	flushingBatchKey = "product:views:flushing:batch"
+	// 每日统计保留 90 天
+	dailyViewsTTL = 90 * 24 * time.Hour
+)
+
+func dailyViewsKey(day string) string {
+	return fmt.Sprintf("product:views:daily:%s", day)
+}
+
+func dailyUVKey(day string, productID uint) string {
+	return fmt.Sprintf("product:uv:%s:%d", day, productID)
+}
+
+// 1. 记录一次浏览（一次 Pipeline 完成所有计数）
+func (d *ViewDao) RecordView(ctx context.Context, productID uint, viewer string, day string) error {
+	pipe := Rdb.Pipeline()
+	pipe.HIncrBy(ctx, pendingViewsKey, strconv.FormatUint(uint64(productID), 10), 1)
+	pipe.HIncrBy(ctx, dailyViewsKey(day), strconv.FormatUint(uint64(productID), 10), 1)
+	pipe.Expire(ctx, dailyViewsKey(day), dailyViewsTTL)
+	if viewer != "" {
+		pipe.PFAdd(ctx, dailyUVKey(day, productID), viewer)
+		pipe.Expire(ctx, dailyUVKey(day, productID), dailyViewsTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 2. 取出待落库的点击量
+// 上一轮落库失败时优先返回上一轮的数据；否则把 pending 原子改名为 flushing 再读取，
+// 改名之后的新浏览会写入新的 pending，互不影响。
+// 每个 flushing 批次带一个批次号，落库时 MySQL 按批次号去重：Ack 失败后重复取到同一批次不会重复计数
+func (d *ViewDao) TakePendingViews(ctx context.Context) (batchID string, counts map[uint]int64, err error) {
+	script := `
+		if redis.call('EXISTS', KEYS[2]) == 0 then
+			if redis.call('EXISTS', KEYS[1]) == 0 then
+				return {}
+			end
+			redis.call('RENAME', KEYS[1], KEYS[2])
+			redis.call('SET', KEYS[3], ARGV[1])
+		end
+		-- 升级前遗留的 flushing 没有批次号，补一个
+		redis.call('SET', KEYS[3], ARGV[1], 'NX')
+		local result = {redis.call('GET', KEYS[3])}
+		local fields = redis.call('HGETALL', KEYS[2])
+		for i = 1, #fields do
+			result[#result + 1] = fields[i]
+		end
+		return result
+	`
+	values, err := Rdb.Eval(ctx, script, []string{pendingViewsKey, flushingViewsKey, flushingBatchKey}, idgen.GenStringID()).StringSlice()
+	if err != nil && err != redis.Nil {
+		return "", nil, err
+	}
+	if len(values) == 0 {
+		return "", nil, nil
+	}
+
+	batchID = values[0]
+	counts = make(map[uint]int64, len(values)/2)
+	for i := 1; i+1 < len(values); i += 2 {
+		productID, err1 := strconv.ParseUint(values[i], 10, 64)
+		count, err2 := strconv.ParseInt(values[i+1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		counts[uint(productID)] = count
+	}
+	return batchID, counts, nil
+}
+
+// 3. 确认落库成功，删除 flushing 及其批次号
+func (d *ViewDao) AckPendingViews(ctx context.Context) error {
+	return Rdb.Del(ctx, flushingViewsKey, flushingBatchKey).Err()
+}
+
+// 4. 查询商品每日浏览量和独立访客数
+func (d *ViewDao) GetDailyStats(ctx context.Context, productID uint, days []string) (views map[string]int64, uv map[string]int64, err error) {
+	pipe := Rdb.Pipeline()
+	viewCmds := make(map[string]*redis.StringCmd, len(days))
+	uvCmds := make(map[string]*redis.IntCmd, len(days))
+	for _, day := range days {
+		viewCmds[day] = pipe.HGet(ctx, dailyViewsKey(day), strconv.FormatUint(uint64(productID), 10))
+		uvCmds[day] = pipe.PFCount(ctx, dailyUVKey(day, productID))
+	}
+	if _, err = pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, nil, err
+	}
+
+	views = make(map[string]int64, len(days))
+	uv = make(map[string]int64, len(days))
+	for _, day := range days {
+		views[day], _ = viewCmds[day].Int64() // 没有记录时为 0
+		uv[day] = uvCmds[day].Val()
+	}
+	return views, uv, nil
+}
@@ -9,37 +9,61 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// parsedTokenKey Token 解析结果在 Context 中的 key
+const parsedTokenKey = "parsed_token"
+
+// parsedToken 一次请求的 Token 解析结果（限流中间件和 JWTAuth 共用，同一请求只解析一次）
+type parsedToken struct {
+	userID uint
+	code   uint32 // 0 表示解析成功，否则为对应的 xerr 错误码
+}
+
 // JWTAuth JWT 认证中间件
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 1️⃣ 从 HTTP Header 获取 Token
-		token := c.GetHeader("Authorization")
-		if token == "" {
-			response.Error(c, xerr.TOKEN_NOT_EXIST, "")
+		// 1️⃣ 解析 Token（限流中间件已解析过时直接复用）
+		token := parseRequestToken(c)
+		if token.code != 0 {
+			response.Error(c, token.code, "")
 			c.Abort() // 拦截请求，不再执行后续 Handler
 			return
 		}
 
-		// 2️⃣ 调用你的 ParseToken 解析
-		claims, err := jwtx.ParseToken(token, config.AppConfig.Jwt.AccessSecret)
-		if err != nil {
-			response.Error(c, xerr.TOKEN_INVALID, "")
-			c.Abort()
-			return
-		}
+		// 2️⃣ ✨核心步骤：注入到 Context ✨
+		c.Set("user_id", token.userID)
 
-		// 3️⃣ 提取 UserID（这里假设是 float64 类型，因为 JSON 默认数字是 float64）
-		uid, ok := claims["uid"].(float64)
-		if !ok {
-			response.Error(c, xerr.TOKEN_USER_ID_ERROR, "")
-			c.Abort()
-			return
-		}
+		// 3️⃣ 继续执行后续的 Handler
+		c.Next()
+	}
+}
 
-		// 4️⃣ ✨核心步骤：注入到 Context ✨
-		c.Set("user_id", uint(uid))
+// parseRequestToken 解析请求头中的 Token，结果缓存在 Context 中
+func parseRequestToken(c *gin.Context) parsedToken {
+	if cached, ok := c.Get(parsedTokenKey); ok {
+		return cached.(parsedToken)
+	}
+	token := doParseRequestToken(c)
+	c.Set(parsedTokenKey, token)
+	return token
+}
 
-		// 5️⃣ 继续执行后续的 Handler
-		c.Next()
+func doParseRequestToken(c *gin.Context) parsedToken {
+	// 1️⃣ 从 HTTP Header 获取 Token
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		return parsedToken{code: xerr.TOKEN_NOT_EXIST}
+	}
+
+	// 2️⃣ 调用你的 ParseToken 解析
+	claims, err := jwtx.ParseToken(token, config.AppConfig.Jwt.AccessSecret)
+	if err != nil {
+		return parsedToken{code: xerr.TOKEN_INVALID}
+	}
+
+	// 3️⃣ 提取 UserID（这里假设是 float64 类型，因为 JSON 默认数字是 float64）
+	uid, ok := claims["uid"].(float64)
+	if !ok {
+		return parsedToken{code: xerr.TOKEN_USER_ID_ERROR}
 	}
+	return parsedToken{userID: uint(uid)}
 }
@@ -2,10 +2,15 @@ package middleware
 
 import (
 	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"xiaomi-mall/config"
 	"xiaomi-mall/internal/dao"
-	"xiaomi-mall/pkg/idgen"
 	"xiaomi-mall/pkg/ratelimit"
 	"xiaomi-mall/pkg/response"
 	"xiaomi-mall/pkg/xerr"
@@ -13,176 +18,184 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// 全局限流器（单例）
-var (
-	GlobalLimiter *ratelimit.SlidingWindowLimiter // 全局限流
-	IPLimiter     *ratelimit.SlidingWindowLimiter // IP 限流
-	UserLimiter   *ratelimit.SlidingWindowLimiter // 用户限流
-	APILimiter    *ratelimit.SlidingWindowLimiter // 接口限流
-)
-
-// InitRateLimiters 初始化限流器
-func InitRateLimiters() {
-	// 全局限流：1 秒内 10000 次
-	GlobalLimiter = ratelimit.NewSlidingWindowLimiter(dao.Rdb, 10000, 1*time.Second)
-
-	// IP 限流：1 秒内 100 次
-	IPLimiter = ratelimit.NewSlidingWindowLimiter(dao.Rdb, 100, 1*time.Second)
-
-	// 用户限流：1 秒内 10 次
-	UserLimiter = ratelimit.NewSlidingWindowLimiter(dao.Rdb, 10, 1*time.Second)
-
-	// 接口限流：1 秒内 1000 次
-	APILimiter = ratelimit.NewSlidingWindowLimiter(dao.Rdb, 1000, 1*time.Second)
+// rateLimitRule 编译后的限流规则
+type rateLimitRule struct {
+	config.RateLimitRule
+	prefix  bool // Path 以 * 结尾，按请求路径前缀匹配
+	limiter ratelimit.Limiter
 }
 
-// GlobalRateLimit 全局限流中间件
-func GlobalRateLimit() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if GlobalLimiter == nil {
-			c.Next()
-			return
-		}
-
-		// 生成请求唯一标识
-		memberID := idgen.GenStringID()
+// 当前生效的限流规则（配置热更新时整体替换）
+var rateLimitRules atomic.Pointer[[]*rateLimitRule]
 
-		// 检查是否允许
-		allowed, count, err := GlobalLimiter.Allow("rate_limit:global", memberID)
-		if err != nil {
-			// Redis 错误不影响业务（降级策略）
-			c.Next()
-			return
-		}
+// InitRateLimiters 按配置初始化限流规则，并在配置文件修改时重新加载
+func InitRateLimiters() {
+	loadRateLimitRules(config.AppConfig.RateLimit)
+	config.OnChange(func(cfg *config.Config) {
+		loadRateLimitRules(cfg.RateLimit)
+	})
+}
 
-		if !allowed {
-			response.Error(c, xerr.RATE_LIMIT_ERROR, fmt.Sprintf("系统繁忙，请稍后重试（当前QPS: %d）", count))
-			c.Abort()
-			return
+// loadRateLimitRules 编译限流规则（配置有误的规则跳过，不影响其他规则）
+func loadRateLimitRules(cfg config.RateLimitConfig) {
+	rules := make([]*rateLimitRule, 0, len(cfg.Rules))
+	if cfg.Enabled {
+		for _, r := range cfg.Rules {
+			if r.Name == "" || r.Path == "" {
+				log.Printf("❌ 限流规则缺少 name 或 path，已跳过: %+v", r)
+				continue
+			}
+			switch r.Dimension {
+			case "ip", "user", "api", "global":
+			default:
+				log.Printf("❌ 限流规则 %s 的维度 %q 不支持，已跳过", r.Name, r.Dimension)
+				continue
+			}
+			limiter, err := ratelimit.New(dao.Rdb, ratelimit.Config{
+				Algorithm: r.Algorithm,
+				Limit:     r.Limit,
+				Window:    r.Window,
+				Burst:     r.Burst,
+			})
+			if err != nil {
+				log.Printf("❌ 限流规则 %s 配置错误，已跳过: %v", r.Name, err)
+				continue
+			}
+			rule := &rateLimitRule{RateLimitRule: r, limiter: limiter}
+			if strings.HasSuffix(r.Path, "*") {
+				rule.prefix = true
+				rule.Path = strings.TrimSuffix(r.Path, "*")
+			}
+			rule.Method = strings.ToUpper(r.Method)
+			rules = append(rules, rule)
 		}
-
-		c.Next()
 	}
+	rateLimitRules.Store(&rules)
+	log.Printf("✅ 限流规则已加载：%d 条", len(rules))
 }
 
-// IPRateLimit IP 限流中间件
-func IPRateLimit() gin.HandlerFunc {
+// RateLimit 限流中间件（在 InitRouter 中全局注册，按配置的规则匹配请求）
+// 响应头：X-RateLimit-Limit / X-RateLimit-Remaining 取命中规则中剩余最少的一条；超限时返回 429 和 Retry-After
+// 被拒绝的请求不消耗任何规则的配额：命中多条规则时先逐条检查（不计数），全部放行后再逐条扣减；
+// 检查和扣减之间其他请求可能用完配额，此时按扣减结果拒绝，已扣减的规则不回退
+func RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if IPLimiter == nil {
+		rules := rateLimitRules.Load()
+		if rules == nil || len(*rules) == 0 {
 			c.Next()
 			return
 		}
 
-		// 获取客户端 IP
-		ip := c.ClientIP()
-		key := fmt.Sprintf("rate_limit:ip:%s", ip)
-		memberID := idgen.GenStringID()
-
-		allowed, count, err := IPLimiter.Allow(key, memberID)
-		if err != nil {
-			c.Next()
-			return
+		// 1️⃣ 收集命中的规则
+		type hit struct {
+			rule *rateLimitRule
+			key  string
 		}
-
-		if !allowed {
-			response.Error(c, xerr.RATE_LIMIT_ERROR, fmt.Sprintf("请求过于频繁，请稍后重试（当前: %d次/秒）", count))
-			c.Abort()
-			return
+		var hits []hit
+		for _, rule := range *rules {
+			if rule.match(c) {
+				hits = append(hits, hit{rule: rule, key: rule.key(c)})
+			}
 		}
-
-		c.Next()
-	}
-}
-
-// UserRateLimit 用户限流中间件（需要先经过 JWT 认证）
-func UserRateLimit() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if UserLimiter == nil {
+		if len(hits) == 0 {
 			c.Next()
 			return
 		}
 
-		// 从 JWT 中间件获取 user_id
-		userID, exists := c.Get("user_id")
-		if !exists {
-			// 未登录用户不限流（或可以用 IP 限流）
-			c.Next()
-			return
+		// 2️⃣ 命中多条规则：先检查，任意一条会拒绝时直接返回（只命中一条时限流脚本拒绝时本身不计数）
+		ctx := c.Request.Context()
+		if len(hits) > 1 {
+			for _, h := range hits {
+				res, err := h.rule.limiter.Peek(ctx, h.key, 1)
+				if err != nil {
+					continue // Redis 错误不影响业务（降级策略）
+				}
+				if !res.Allowed {
+					rejectRateLimited(c, res)
+					return
+				}
+			}
 		}
 
-		key := fmt.Sprintf("rate_limit:user:%v", userID)
-		memberID := idgen.GenStringID()
-
-		allowed, count, err := UserLimiter.Allow(key, memberID)
-		if err != nil {
-			c.Next()
-			return
+		// 3️⃣ 扣减配额
+		var tightest *ratelimit.Result
+		for _, h := range hits {
+			res, err := h.rule.limiter.Allow(ctx, h.key)
+			if err != nil {
+				// Redis 错误不影响业务（降级策略）
+				continue
+			}
+			if !res.Allowed {
+				rejectRateLimited(c, res)
+				return
+			}
+			if tightest == nil || res.Remaining < tightest.Remaining {
+				tightest = res
+			}
 		}
 
-		if !allowed {
-			response.Error(c, xerr.RATE_LIMIT_ERROR, fmt.Sprintf("操作过于频繁，请稍后重试（当前: %d次/秒）", count))
-			c.Abort()
-			return
+		if tightest != nil {
+			setRateLimitHeaders(c, tightest)
 		}
-
 		c.Next()
 	}
 }
 
-// APIRateLimit 接口限流中间件（针对特定接口）
-// 使用方式：r.GET("/api/seckill", APIRateLimit("/api/seckill", 100, 1*time.Second), handler)
-func APIRateLimit(apiPath string, limit int, window time.Duration) gin.HandlerFunc {
-	limiter := ratelimit.NewSlidingWindowLimiter(dao.Rdb, limit, window)
-
-	return func(c *gin.Context) {
-		key := fmt.Sprintf("rate_limit:api:%s", apiPath)
-		memberID := idgen.GenStringID()
-
-		allowed, count, err := limiter.Allow(key, memberID)
-		if err != nil {
-			c.Next()
-			return
-		}
-
-		if !allowed {
-			response.Error(c, xerr.RATE_LIMIT_ERROR, fmt.Sprintf("接口请求过于频繁（当前: %d次/%v）", count, window))
-			c.Abort()
-			return
-		}
+// rejectRateLimited 返回 429
+func rejectRateLimited(c *gin.Context, res *ratelimit.Result) {
+	setRateLimitHeaders(c, res)
+	c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(res.RetryAfter)))
+	response.ErrorWithStatus(c, http.StatusTooManyRequests, xerr.RATE_LIMIT_ERROR, "")
+}
 
-		c.Next()
+// match 请求是否命中规则
+func (r *rateLimitRule) match(c *gin.Context) bool {
+	if r.Method != "" && r.Method != c.Request.Method {
+		return false
 	}
+	if r.prefix {
+		return strings.HasPrefix(c.Request.URL.Path, r.Path)
+	}
+	return c.FullPath() == r.Path
 }
 
-// SeckillRateLimit 秒杀专用限流（更严格）
-// 1 秒内单个用户最多 1 次
-func SeckillRateLimit() gin.HandlerFunc {
-	limiter := ratelimit.NewSlidingWindowLimiter(dao.Rdb, 1, 1*time.Second)
-
-	return func(c *gin.Context) {
-		// 必须登录
-		userID, exists := c.Get("user_id")
-		if !exists {
-			response.Error(c, xerr.TOKEN_NOT_EXIST, "请先登录")
-			c.Abort()
-			return
+// key 按维度生成限流 key
+func (r *rateLimitRule) key(c *gin.Context) string {
+	var subject string
+	switch r.Dimension {
+	case "ip":
+		subject = "ip:" + c.ClientIP()
+	case "user":
+		if userID, ok := requestUserID(c); ok {
+			subject = fmt.Sprintf("user:%d", userID)
+		} else {
+			subject = "ip:" + c.ClientIP() // 未登录按 IP 限流
 		}
+	case "api":
+		subject = "api:" + c.Request.Method + ":" + c.FullPath()
+	default:
+		subject = "global"
+	}
+	return "rate_limit:" + r.Name + ":" + subject
+}
 
-		key := fmt.Sprintf("rate_limit:seckill:user:%v", userID)
-		memberID := idgen.GenStringID()
-
-		allowed, _, err := limiter.Allow(key, memberID)
-		if err != nil {
-			c.Next()
-			return
-		}
+// requestUserID 从 Token 中解析用户 ID（限流中间件在 JWTAuth 之前执行，解析结果缓存在 Context 中由 JWTAuth 复用）
+func requestUserID(c *gin.Context) (uint, bool) {
+	token := parseRequestToken(c)
+	return token.userID, token.code == 0
+}
 
-		if !allowed {
-			response.Error(c, xerr.RATE_LIMIT_ERROR, "操作过于频繁，请1秒后重试")
-			c.Abort()
-			return
-		}
+// setRateLimitHeaders 写入配额响应头
+func setRateLimitHeaders(c *gin.Context, res *ratelimit.Result) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+}
 
-		c.Next()
+// retryAfterSeconds Retry-After 只支持整数秒，向上取整且至少 1 秒
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
 	}
+	return seconds
 }
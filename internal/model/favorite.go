@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Favorite 商品收藏（取消收藏直接物理删除，避免软删除和唯一索引冲突）
+type Favorite struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_product" json:"user_id"`
+	ProductID uint      `gorm:"not null;uniqueIndex:idx_user_product;index" json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Notification 站内通知
+type Notification struct {
+	gorm.Model
+	UserID  uint   `gorm:"not null;index:idx_user_read" json:"user_id"`
+	Type    string `gorm:"size:32;not null" json:"type"`                     // 通知类型，如 price_drop
+	Title   string `json:"title"`                                            // 标题
+	Content string `gorm:"size:1000" json:"content"`                         // 正文
+	Payload string `gorm:"type:text" json:"payload"`                         // 业务数据（JSON），前端按 Type 解析
+	IsRead  bool   `gorm:"default:false;index:idx_user_read" json:"is_read"` // 是否已读
+}
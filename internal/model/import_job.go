@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ImportJob 批量导入任务（后台执行，前端轮询进度）
+type ImportJob struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	Type          string     `gorm:"size:32;not null;index" json:"type"` // 导入类型：product
+	FileName      string     `gorm:"size:255" json:"file_name"`
+	Status        int8       `gorm:"not null;default:0;index" json:"status"` // 0:排队中 1:执行中 2:已完成 3:失败
+	TotalRows     int        `gorm:"not null;default:0" json:"total_rows"`   // 数据行数（不含表头）
+	ProcessedRows int        `gorm:"not null;default:0" json:"processed_rows"`
+	SuccessRows   int        `gorm:"not null;default:0" json:"success_rows"`
+	FailedRows    int        `gorm:"not null;default:0" json:"failed_rows"`
+	Message       string     `gorm:"size:500" json:"message"`  // 任务级错误（文件无法解析、表头缺列等）
+	ErrorReport   string     `gorm:"type:mediumtext" json:"-"` // 逐行错误报告（CSV），通过下载接口获取
+	FinishedAt    *time.Time `json:"finished_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
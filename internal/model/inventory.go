@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// InventoryMovement SKU 库存流水（只追加），与库存变更在同一事务内写入
+type InventoryMovement struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	ProductID    uint      `gorm:"not null;index" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;index" json:"product_sku_id"`
+	Delta        int       `gorm:"not null" json:"delta"`                // 库存变化量（正数入库，负数出库）
+	Reason       string    `gorm:"size:32;not null;index" json:"reason"` // 变更原因，见 constants.STOCK_REASON_*
+	OrderNum     string    `gorm:"size:64;index" json:"order_num"`       // 关联订单号（秒杀预留时为秒杀活动标识）
+	Operator     string    `gorm:"size:64" json:"operator"`              // 操作人（用户操作为 user:<ID>，系统任务为 system，管理员为填写的操作人）
+	StockAfter   int       `gorm:"not null" json:"stock_after"`          // 变更后的库存
+	Remark       string    `gorm:"size:255" json:"remark"`               // 备注（管理员调整原因等）
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockReservation 下单时预占的 SKU 库存（订单号 + SKU 唯一），支付时转为实际扣减，取消 / 超时时释放
+type StockReservation struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	OrderNum     string    `gorm:"size:64;not null;uniqueIndex:idx_order_sku" json:"order_num"`
+	ProductID    uint      `gorm:"not null" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;uniqueIndex:idx_order_sku;index" json:"product_sku_id"`
+	Quantity     int       `gorm:"not null" json:"quantity"`
+	Status       int8      `gorm:"not null;default:0;index:idx_status_expire" json:"status"` // 0:预占中 1:已转为销售 2:已释放
+	ExpireTime   time.Time `gorm:"not null;index:idx_status_expire" json:"expire_time"`      // 预占到期时间（与订单支付截止时间一致）
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StockAlert 低库存告警（同一 SKU 同时只有一条未恢复的告警，可售库存回到阈值以上后恢复）
+type StockAlert struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	ProductID    uint       `gorm:"not null;index" json:"product_id"`
+	ProductSkuID uint       `gorm:"not null;index" json:"product_sku_id"`
+	OpenSkuID    *uint      `gorm:"uniqueIndex" json:"-"`                   // 未恢复时等于 ProductSkuID，恢复后置空（唯一索引保证告警不重复）
+	Available    int        `gorm:"not null" json:"available"`              // 触发时的可售库存
+	Threshold    int        `gorm:"not null" json:"threshold"`              // 触发时的阈值
+	Status       int8       `gorm:"not null;default:0;index" json:"status"` // 0:告警中 1:已恢复
+	ResolvedAt   *time.Time `json:"resolved_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// StockSubscription 到货提醒订阅（可售库存从 0 变为大于 0 时发送站内通知）
+type StockSubscription struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_user_sku" json:"user_id"`
+	ProductID    uint       `gorm:"not null" json:"product_id"`
+	ProductSkuID uint       `gorm:"not null;uniqueIndex:idx_user_sku;index:idx_sku_status" json:"product_sku_id"`
+	Status       int8       `gorm:"not null;default:0;index:idx_sku_status" json:"status"` // 0:等待到货 1:已通知
+	NotifiedAt   *time.Time `json:"notified_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
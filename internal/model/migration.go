@@ -10,10 +10,25 @@ func Migrate(db *gorm.DB) error {
 		&Category{},
 		&Product{},
 		&ProductSku{},
+		&ProductImage{},
+		&ProductContent{},
+		&ViewFlushBatch{},
+		&InventoryMovement{},
+		&StockReservation{},
+		&StockAlert{},
+		&StockSubscription{},
+		&PriceRule{},
+		&PriceHistory{},
+		&Carousel{},
 		&Order{},
 		&OrderItem{},
 		&SeckillProduct{},
 		&SeckillOrder{},
+		&Review{},
+		&RatingStat{},
+		&Favorite{},
+		&Notification{},
+		&ImportJob{},
 	)
 	return err
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PriceRule SKU 定时改价 / 限时价（在 [StartTime, EndTime) 内以 Price 售卖，同一 SKU 的生效规则时间不重叠）
+type PriceRule struct {
+	gorm.Model
+	ProductID    uint      `gorm:"not null;index" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;index:idx_sku_time" json:"product_sku_id"`
+	Price        int64     `gorm:"not null" json:"price"` // 规则价格，单位：分
+	StartTime    time.Time `gorm:"not null;index:idx_sku_time;index" json:"start_time"`
+	EndTime      time.Time `gorm:"not null;index" json:"end_time"`
+	Status       int8      `gorm:"not null;default:1" json:"status"` // 0:已取消 1:有效
+	Remark       string    `gorm:"size:255" json:"remark"`
+}
+
+// PriceHistory SKU 实际售价变更记录（只追加），用于查询任意时刻顾客看到的价格
+type PriceHistory struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	ProductID    uint      `gorm:"not null;index" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;index:idx_sku_effective" json:"product_sku_id"`
+	Price        int64     `gorm:"not null" json:"price"`      // 实际售价，单位：分
+	BasePrice    int64     `gorm:"not null" json:"base_price"` // 当时的 SKU 原价
+	RuleID       uint      `gorm:"not null;default:0" json:"rule_id"`
+	Source       string    `gorm:"size:16;not null" json:"source"` // 变更来源：base（原价调整）/ rule（价格规则生效或结束）
+	EffectiveAt  time.Time `gorm:"not null;index:idx_sku_effective" json:"effective_at"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Category 商品分类
 type Category struct {
@@ -12,34 +16,75 @@ type Category struct {
 // Product (SPU) 商品主表
 type Product struct {
 	gorm.Model
-	Name          string `gorm:"size:255;index" json:"name"` // 商品名
-	CategoryID    uint   `gorm:"not null;index" json:"category_id"`
-	Title         string `json:"title"`
-	Info          string `gorm:"size:1000" json:"info"` // 详细描述
-	ImgPath       string `json:"img_path"`
-	Price         int64  `json:"price"`                                 // 展示价格，单位：分
-	DiscountPrice int64  `json:"discount_price"`                        // 折扣价，单位：分
-	OnSale        bool   `gorm:"default:false" json:"on_sale"`          // 是否上架
-	Num           int    `json:"num"`                                   // 销量
-	ClickNum      int    `json:"click_num"`                             // 点击量
-	IsSeckill     bool   `gorm:"default:false;index" json:"is_seckill"` // 是否秒杀商品
+	SpuCode       string     `gorm:"size:64;index" json:"spu_code"` // 商家 SPU 编码（批量导入时按此归并 SKU，可为空）
+	Name          string     `gorm:"size:255;index" json:"name"`    // 商品名
+	CategoryID    uint       `gorm:"not null;index" json:"category_id"`
+	Title         string     `json:"title"`
+	Info          string     `gorm:"size:1000" json:"info"` // 详细描述
+	ImgPath       string     `json:"img_path"`
+	Price         int64      `json:"price"`                                 // 展示价格，单位：分
+	DiscountPrice int64      `json:"discount_price"`                        // 折扣价，单位：分
+	OnSale        bool       `gorm:"default:false" json:"on_sale"`          // 是否上架
+	OnSaleAt      *time.Time `gorm:"index" json:"on_sale_at"`               // 定时上架时间（到点后由定时任务上架并清空）
+	OffSaleAt     *time.Time `gorm:"index" json:"off_sale_at"`              // 定时下架时间（到点后由定时任务下架并清空）
+	Num           int        `json:"num"`                                   // 销量
+	ClickNum      int        `json:"click_num"`                             // 点击量
+	FavoriteNum   int        `gorm:"default:0" json:"favorite_num"`         // 收藏数
+	IsSeckill     bool       `gorm:"default:false;index" json:"is_seckill"` // 是否秒杀商品
 }
 
 // ProductSku (SKU) 商品规格表 —— 库存管理的原子单位
 type ProductSku struct {
 	gorm.Model
-	ProductID uint   `gorm:"not null;index" json:"product_id"`
-	Title     string `json:"title"`                       // 规格名，如 "红色+64G"
-	Price     int64  `json:"price"`                       // 价格，单位：分
-	Stock     int    `gorm:"check:stock>=0" json:"stock"` // 库存，数据库层面约束不能小于0
-	Code      string `json:"code"`                        // 商家编码
-	Version   int    `gorm:"default:0" json:"version"`    // 乐观锁版本号
-	ImgPath   string `json:"img_path"`                    // 图片路径
+	ProductID         uint   `gorm:"not null;index" json:"product_id"`
+	Title             string `json:"title"`                                         // 规格名，如 "红色+64G"
+	Price             int64  `json:"price"`                                         // 价格，单位：分
+	Stock             int    `gorm:"check:stock>=0" json:"stock"`                   // 库存，数据库层面约束不能小于0
+	Reserved          int    `gorm:"not null;default:0" json:"reserved"`            // 被未支付订单预占的库存，可售库存 = Stock - Reserved
+	Code              string `gorm:"size:64;index" json:"code"`                     // 商家编码（批量导入按此 upsert）
+	Version           int    `gorm:"default:0" json:"version"`                      // 乐观锁版本号
+	StockMode         int8   `gorm:"not null;default:0" json:"stock_mode"`          // 下单预占方式 0:乐观锁（校验版本号） 1:条件扣减（只校验可售库存，适合热点 SKU）
+	LowStockThreshold int    `gorm:"not null;default:0" json:"low_stock_threshold"` // 低库存告警阈值，可售库存 <= 阈值时告警，0 表示不告警
+	ImgPath           string `json:"img_path"`                                      // 图片路径
+}
+
+// ProductImage 商品图集（ProductSkuID = 0 为商品主图集，否则为该 SKU 的图集），按 Sort 升序展示
+type ProductImage struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	ProductID    uint      `gorm:"not null;index:idx_product_sku_sort" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;default:0;index:idx_product_sku_sort" json:"product_sku_id"`
+	URL          string    `gorm:"size:500;not null" json:"url"`
+	Sort         int       `gorm:"not null;default:0;index:idx_product_sku_sort" json:"sort"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ProductContent 商品图文详情（与 Product 分表存储，列表查询不会读到大字段）
+type ProductContent struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ProductID uint      `gorm:"not null;uniqueIndex" json:"product_id"`
+	Blocks    string    `gorm:"type:mediumtext" json:"blocks"` // 内容块数组（JSON），见 types.ContentBlock
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// Carousel 轮播图 (首页广告)
-// type Carousel struct {
-// 	gorm.Model
-// 	ImgPath   string `json:"img_path"`
-// 	ProductID uint   `json:"product_id"` // 点击跳转到哪个商品
-// }
+// Carousel 轮播图 / 运营位 (首页轮播、分类精选、新品推荐)
+type Carousel struct {
+	gorm.Model
+	Slot           string    `gorm:"size:32;not null;index:idx_slot_status" json:"slot"` // 运营位：home_carousel / category_top / new_arrival
+	SlotCategoryID uint      `gorm:"default:0;index" json:"slot_category_id"`            // 分类精选所属分类（其他运营位为 0）
+	Title          string    `json:"title"`                                              // 标题（可选）
+	ImgPath        string    `json:"img_path"`                                           // 图片
+	TargetType     string    `gorm:"size:16;not null" json:"target_type"`                // 跳转类型：product / category / url
+	ProductID      uint      `json:"product_id"`                                         // 点击跳转到哪个商品
+	CategoryID     uint      `json:"category_id"`                                        // 点击跳转到哪个分类
+	URL            string    `gorm:"size:500" json:"url"`                                // 点击跳转的链接
+	StartTime      time.Time `gorm:"not null;index" json:"start_time"`                   // 开始展示时间
+	EndTime        time.Time `gorm:"not null;index" json:"end_time"`                     // 结束展示时间
+	Sort           int       `gorm:"default:0" json:"sort"`                              // 排序，越小越靠前
+	Status         int8      `gorm:"default:1;index:idx_slot_status" json:"status"`      // 0:停用 1:启用 2:已过期
+}
+
+// ViewFlushBatch 已落库的点击量批次（同一批次重复落库时跳过，Ack 失败重试不会重复计数）
+type ViewFlushBatch struct {
+	BatchID   string    `gorm:"primaryKey;size:32" json:"batch_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review 商品评价（一个订单商品只能评价一次）
+type Review struct {
+	gorm.Model
+	OrderItemID  uint   `gorm:"not null;uniqueIndex" json:"order_item_id"`
+	OrderNum     string `gorm:"not null;index" json:"order_num"`
+	UserID       uint   `gorm:"not null;index" json:"user_id"`
+	ProductID    uint   `gorm:"not null;index:idx_product_star" json:"product_id"`
+	ProductSkuID uint   `gorm:"not null;index" json:"product_sku_id"`
+	SkuTitle     string `json:"sku_title"`                                   // 购买时的规格（快照）
+	Star         int    `gorm:"not null;index:idx_product_star" json:"star"` // 1-5 星
+	Content      string `gorm:"type:text" json:"content"`                    // 评价内容
+	Images       string `gorm:"type:text" json:"images"`                     // 图片 URL 列表（JSON 数组）
+	HasImage     bool   `gorm:"default:false;index" json:"has_image"`        // 是否有图（有图筛选用）
+
+	// 追评（只能追评一次）
+	FollowUpContent string     `gorm:"type:text" json:"follow_up_content"`
+	FollowUpImages  string     `gorm:"type:text" json:"follow_up_images"` // JSON 数组
+	FollowUpTime    *time.Time `json:"follow_up_time"`
+
+	// 商家回复
+	Reply     string     `gorm:"type:text" json:"reply"`
+	ReplyTime *time.Time `json:"reply_time"`
+}
+
+// RatingStat 评分汇总（增量维护，ProductSkuID = 0 表示整个商品）
+type RatingStat struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	ProductID    uint      `gorm:"not null;uniqueIndex:idx_product_sku" json:"product_id"`
+	ProductSkuID uint      `gorm:"not null;default:0;uniqueIndex:idx_product_sku" json:"product_sku_id"`
+	ReviewCount  int64     `gorm:"not null;default:0" json:"review_count"` // 评价数
+	StarSum      int64     `gorm:"not null;default:0" json:"star_sum"`     // 星级总和（平均分 = StarSum / ReviewCount）
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"xiaomi-mall/internal/dao"
+)
+
+// ErrNotFound 数据不存在（加载函数返回它表示应缓存空值，读取到空值缓存时也返回它）
+var ErrNotFound = errors.New("cache: 数据不存在")
+
+// Options 缓存读取选项
+type Options struct {
+	TTL           time.Duration // 缓存有效期（逻辑过期模式下为逻辑有效期）
+	Jitter        time.Duration // TTL 随机抖动上限，避免大量 key 同时过期（缓存雪崩）
+	NullTTL       time.Duration // 空值缓存有效期（防止缓存穿透），0 表示不缓存空值
+	LogicalExpire bool          // 逻辑过期模式：过期后先返回旧值，再异步刷新（热点 key 防击穿）
+	Local         bool          // 是否启用进程内一级缓存（需先调用 InitLocal）
+}
+
+// entry Redis 中实际存储的缓存条目
+type entry struct {
+	Data     json.RawMessage `json:"d,omitempty"`
+	Null     bool            `json:"n,omitempty"` // 空值标记（替代原来的 "null" 字符串）
+	ExpireAt int64           `json:"e,omitempty"` // 逻辑过期时间（Unix 秒），0 表示未启用逻辑过期
+}
+
+const (
+	// 逻辑过期模式下物理过期的兜底时间（长时间无人访问的 key 最终会被清理）
+	logicalRetention = 24 * time.Hour
+	// 异步刷新分布式锁的有效期
+	refreshLockTTL = 10 * time.Second
+)
+
+// 进程内合并同 key 的并发回源
+var sf group
+
+// Fetch 读取缓存，未命中时回源并写入缓存（Cache-Aside）
+//   - 启用 Local 时先查进程内 LRU，再查 Redis
+//   - 同一 key 的并发回源通过 Singleflight 合并，只有一个请求会查询数据库
+//   - load 返回 ErrNotFound 时缓存空值，后续请求直接返回 ErrNotFound
+//   - 逻辑过期模式下，过期数据会立即返回，并由一个后台协程刷新
+//
+// 每个调用方拿到的都是独立反序列化出来的副本，可以放心修改
+func Fetch[T any](ctx context.Context, key string, opts Options, load func() (*T, error)) (*T, error) {
+	useLocal := opts.Local && local != nil
+
+	// 1️⃣ 读取进程内缓存
+	if useLocal {
+		if item, ok := local.get(key); ok {
+			if item.null {
+				return nil, ErrNotFound
+			}
+			var v T
+			if err := json.Unmarshal(item.data, &v); err == nil {
+				return &v, nil
+			}
+			local.remove(key)
+		}
+	}
+
+	// 2️⃣ 读取 Redis
+	if e, ok := getEntry(ctx, key); ok {
+		if e.Null {
+			if useLocal {
+				local.set(key, nil, true)
+			}
+			return nil, ErrNotFound
+		}
+		var v T
+		if err := json.Unmarshal(e.Data, &v); err == nil {
+			// 逻辑过期：返回旧值，异步刷新（旧值不进本地缓存）
+			if e.ExpireAt > 0 && time.Now().Unix() >= e.ExpireAt {
+				refreshAsync(key, opts, load)
+			} else if useLocal {
+				local.set(key, e.Data, false)
+			}
+			return &v, nil
+		}
+		// 数据损坏，删除后回源
+		dao.Rdb.Del(ctx, key)
+	}
+
+	// 3️⃣ 缓存未命中，合并回源
+	data, err, _ := sf.Do(key, func() (interface{}, error) {
+		return loadAndSet(ctx, key, opts, load)
+	})
+	if useLocal {
+		if errors.Is(err, ErrNotFound) {
+			local.set(key, nil, true)
+		} else if err == nil {
+			local.set(key, data.([]byte), false)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var v T
+	if err := json.Unmarshal(data.([]byte), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// loadAndSet 回源并写入缓存，返回序列化后的数据
+func loadAndSet[T any](ctx context.Context, key string, opts Options, load func() (*T, error)) ([]byte, error) {
+	v, err := load()
+	if errors.Is(err, ErrNotFound) {
+		if opts.NullTTL > 0 {
+			setEntry(ctx, key, entry{Null: true}, opts.NullTTL)
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	e := entry{Data: data}
+	ttl := withJitter(opts.TTL, opts.Jitter)
+	if opts.LogicalExpire {
+		e.ExpireAt = time.Now().Add(ttl).Unix()
+		ttl += logicalRetention
+	}
+	setEntry(ctx, key, e, ttl)
+
+	return data, nil
+}
+
+// refreshAsync 异步刷新逻辑过期的缓存
+// 进程内用 Singleflight 去重，跨实例用 Redis SETNX 互斥，保证同一时刻只有一个刷新
+func refreshAsync[T any](key string, opts Options, load func() (*T, error)) {
+	go func() {
+		sf.Do("refresh:"+key, func() (interface{}, error) {
+			ctx := context.Background()
+			lockKey := "lock:" + key
+
+			ok, err := dao.Rdb.SetNX(ctx, lockKey, 1, refreshLockTTL).Result()
+			if err != nil || !ok {
+				return nil, nil // 其他实例正在刷新
+			}
+			defer dao.Rdb.Del(ctx, lockKey)
+
+			if _, err := loadAndSet(ctx, key, opts, load); err != nil && !errors.Is(err, ErrNotFound) {
+				log.Printf("⚠️  缓存异步刷新失败: %s, 错误: %v", key, err)
+			}
+			return nil, nil
+		})
+	}()
+}
+
+func getEntry(ctx context.Context, key string) (*entry, bool) {
+	raw, err := dao.Rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		// redis.Nil 表示未命中；其他错误（如连接失败）降级为回源
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		dao.Rdb.Del(ctx, key)
+		return nil, false
+	}
+	return &e, true
+}
+
+func setEntry(ctx context.Context, key string, e entry, ttl time.Duration) {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// 缓存写入失败不影响业务
+	dao.Rdb.Set(ctx, key, raw, ttl)
+}
+
+// withJitter 在 TTL 基础上加一个 [0, jitter) 的随机时长
+func withJitter(ttl, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int64N(int64(jitter)))
+}
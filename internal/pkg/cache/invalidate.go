@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/dao"
+)
+
+// 缓存失效广播频道：所有实例订阅，收到后删除本地一级缓存
+const invalidateChannel = "cache:invalidate"
+
+// 进程内一级缓存（InitLocal 之前为 nil，此时只使用 Redis）
+var local *localCache
+
+// InitLocal 初始化进程内 LRU 缓存
+// size: 最大条目数
+// ttl: 本地缓存有效期（兜底，防止漏收失效广播时长时间读到旧数据）
+func InitLocal(size int, ttl time.Duration) {
+	if size <= 0 || ttl <= 0 {
+		return
+	}
+	local = newLocalCache(size, ttl)
+}
+
+// Invalidate 删除缓存（Redis + 本地），并广播给其他实例删除本地副本
+// 管理端修改商品、SKU、分类、秒杀活动后调用
+func Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if local != nil {
+		local.remove(keys...)
+	}
+
+	if err := dao.Rdb.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return dao.Rdb.Publish(ctx, invalidateChannel, msg).Err()
+}
+
+// StartInvalidationListener 订阅失效广播，删除本地缓存
+func StartInvalidationListener() {
+	if local == nil {
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		pubsub := dao.Rdb.Subscribe(ctx, invalidateChannel)
+		defer pubsub.Close()
+
+		log.Println("✅ 缓存失效广播监听启动")
+
+		// Channel() 内部会自动重连
+		for msg := range pubsub.Channel() {
+			var keys []string
+			if err := json.Unmarshal([]byte(msg.Payload), &keys); err != nil {
+				log.Printf("⚠️  缓存失效消息解析失败: %v", err)
+				continue
+			}
+			local.remove(keys...)
+		}
+	}()
+}
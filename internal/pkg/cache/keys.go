@@ -0,0 +1,22 @@
+package cache
+
+import "fmt"
+
+// ============ 缓存 key 统一定义 ============
+// 读写和失效都通过这里拼 key，避免各处格式不一致
+
+// ProductDetailKey 商品详情缓存
+func ProductDetailKey(productID uint) string {
+	return fmt.Sprintf("product:detail:%d", productID)
+}
+
+// SkuDetailKey SKU 详情缓存
+func SkuDetailKey(skuID uint) string {
+	return fmt.Sprintf("sku:detail:%d", skuID)
+}
+
+// CategoryListKey 分类列表缓存
+const CategoryListKey = "category:list"
+
+// HomeKey 首页聚合数据缓存
+const HomeKey = "home:index"
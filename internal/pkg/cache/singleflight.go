@@ -0,0 +1,44 @@
+package cache
+
+import "sync"
+
+// call 一次正在进行中的加载
+type call struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// group 合并同一个 key 的并发加载（Singleflight）
+// 同一时刻相同 key 只有一个请求真正执行 fn，其余请求等待并共享结果
+type group struct {
+	mu sync.Mutex
+	m  map[string]*call
+}
+
+// Do 执行并合并同 key 的调用
+// shared 表示结果是否被多个调用方共享
+func (g *group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	if c, ok := g.m[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := new(call)
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+
+	return c.val, c.err, false
+}
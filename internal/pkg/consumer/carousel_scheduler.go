@@ -0,0 +1,54 @@
+package consumer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/cache"
+)
+
+// StartCarouselScheduler 启动运营位排期任务
+// 1. 结束时间已到的运营位标记为已过期
+// 2. 有运营位到达结束时间或开始时间时，失效首页缓存，让排期按分钟级生效
+func StartCarouselScheduler() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		log.Println("✅ 运营位排期任务启动")
+
+		lastRun := time.Now()
+		for now := range ticker.C {
+			scheduleCarousels(lastRun, now)
+			lastRun = now
+		}
+	}()
+}
+
+// scheduleCarousels 处理 (lastRun, now] 区间内的排期变化
+func scheduleCarousels(lastRun, now time.Time) {
+	changed := false
+
+	// 1️⃣ 标记过期
+	expired, err := dao.Carousel.ExpireCarousels(now)
+	if err != nil {
+		log.Printf("❌ 运营位排期：标记过期失败: %v", err)
+	} else if expired > 0 {
+		log.Printf("✅ 运营位排期：%d 个运营位已过期", expired)
+		changed = true
+	}
+
+	// 2️⃣ 新开始展示的运营位
+	started, err := dao.Carousel.CountStartedBetween(lastRun, now)
+	if err != nil {
+		log.Printf("❌ 运营位排期：查询开始展示的运营位失败: %v", err)
+	} else if started > 0 {
+		changed = true
+	}
+
+	if changed {
+		cache.Invalidate(context.Background(), cache.HomeKey)
+	}
+}
@@ -2,83 +2,75 @@ package consumer
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
+	"xiaomi-mall/config"
 	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/delayqueue"
 	"xiaomi-mall/internal/service/userService"
-
-	"github.com/go-redis/redis/v8"
 )
 
-// StartSeckillOrderTimeoutScanner 启动秒杀订单超时扫描器
+// StartSeckillOrderTimeoutScanner 启动订单超时关单队列（多实例部署时每个订单只会被一个 worker 认领处理）
 func StartSeckillOrderTimeoutScanner() {
-	go func() {
-		ticker := time.NewTicker(1 * time.Second) // 每秒扫描一次
-		defer ticker.Stop()
-
-		log.Println("✅ 秒杀订单超时扫描器启动")
+	// 启动时先按 MySQL 补齐延迟队列（Redis 数据丢失后重启即可恢复）
+	if added, err := userService.Order.RebuildDelayQueue(); err != nil {
+		log.Printf("❌ 重建订单延迟队列失败: %v", err)
+	} else if added > 0 {
+		log.Printf("✅ 订单延迟队列已补齐 %d 个待支付订单", added)
+	}
 
-		for range ticker.C {
-			scanExpiredSeckillOrders()
-		}
-	}()
+	cfg := config.AppConfig.Order
+	dao.OrderTimeoutQueue.Start(handleExpiredOrder, delayqueue.Options{
+		Workers:         cfg.TimeoutWorkers,
+		BatchSize:       cfg.TimeoutBatchSize,
+		Lease:           time.Duration(cfg.TimeoutLeaseSeconds) * time.Second,
+		RetryBackoff:    time.Duration(cfg.TimeoutRetrySeconds) * time.Second,
+		RetryMaxBackoff: time.Duration(cfg.TimeoutMaxRetrySeconds) * time.Second,
+	})
 }
 
-// scanExpiredSeckillOrders 扫描并处理过期的秒杀订单
-func scanExpiredSeckillOrders() {
-	ctx := context.Background()
-	now := float64(time.Now().Unix())
+// handleExpiredOrder 关闭一个过期订单（返回 error 时由延迟队列退避重试）
+func handleExpiredOrder(_ context.Context, orderNum string) error {
+	if err := userService.Order.CloseExpiredOrder(orderNum); err != nil {
+		return err
+	}
+	log.Printf("✅ 订单关闭成功：%s", orderNum)
+	return nil
+}
 
-	// 1. 查询已过期的订单（score <= 当前时间）
-	orders, err := dao.Rdb.ZRangeByScore(ctx, "order:delay:queue", &redis.ZRangeBy{
-		Min: "-inf",                 // 最小值
-		Max: fmt.Sprintf("%f", now), // 当前时间
-	}).Result()
+// StartPayReminderQueue 启动待支付提醒队列（订单超时前提醒用户支付）
+func StartPayReminderQueue() {
+	dao.PayReminderQueue.Start(func(_ context.Context, orderNum string) error {
+		return userService.Order.SendPayReminder(orderNum)
+	}, delayqueue.Options{
+		Workers: 2,
+		Lease:   time.Duration(config.AppConfig.Order.TimeoutLeaseSeconds) * time.Second,
+	})
+}
 
-	if err != nil || len(orders) == 0 {
-		return // 无过期订单或查询失败
-	}
+// StartAutoConfirmQueue 启动自动确认收货队列（发货后到期未确认收货的订单自动完成）
+func StartAutoConfirmQueue() {
+	dao.AutoConfirmQueue.Start(func(_ context.Context, orderNum string) error {
+		return userService.Order.AutoConfirmOrder(orderNum)
+	}, delayqueue.Options{
+		Workers: 4,
+		Lease:   time.Duration(config.AppConfig.Order.TimeoutLeaseSeconds) * time.Second,
+	})
+}
 
-	// 2. 逐个处理过期订单
-	for _, orderNum := range orders {
-		// 2.1 判断订单类型
-		orderType, err := getOrderType(orderNum)
-		if err != nil {
-			// 订单不存在，从队列中移除
-			dao.Rdb.ZRem(ctx, "order:delay:queue", orderNum)
-			continue
-		}
+// StartOrderTimeoutCompensator 启动订单补偿任务（低频扫描 MySQL，处理延迟队列漏掉的超时关单和自动确认收货）
+func StartOrderTimeoutCompensator() {
+	interval := time.Duration(config.AppConfig.Order.CompensateIntervalSeconds) * time.Second
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
 
-		// 2.2 根据订单类型选择关单方法
-		var closeErr error
-		if orderType == 2 { // 秒杀订单
-			log.Printf("⏰ 发现过期秒杀订单：%s", orderNum)
-			closeErr = userService.Seckill.CloseSeckillOrder(orderNum)
-		} else { // 普通订单
-			log.Printf("⏰ 发现过期普通订单：%s", orderNum)
-			closeErr = userService.Order.CloseOrder(orderNum)
-		}
+		log.Println("✅ 订单补偿任务启动")
 
-		// 2.3 处理结果
-		if closeErr == nil {
-			// 关单成功，从队列中移除
-			dao.Rdb.ZRem(ctx, "order:delay:queue", orderNum)
-			log.Printf("✅ 订单关闭成功：%s", orderNum)
-		} else {
-			log.Printf("❌ 订单关闭失败：%s, 错误：%v", orderNum, closeErr)
-			// 关单失败，下次继续尝试
+		for range ticker.C {
+			userService.Order.CloseOverdueOrders()
+			userService.Order.ConfirmOverdueOrders()
 		}
-	}
-}
-
-// getOrderType 获取订单类型（1=普通订单，2=秒杀订单）
-func getOrderType(orderNum string) (int, error) {
-	var orderType int
-	err := dao.DB.Table("orders").
-		Select("type").
-		Where("order_num = ?", orderNum).
-		Scan(&orderType).Error
-	return orderType, err
+	}()
 }
@@ -0,0 +1,28 @@
+package consumer
+
+import (
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/service/adminService"
+)
+
+// 价格规则检查间隔（展示价格最多延迟一个间隔，下单始终按下单时刻实时计算）
+const priceRuleInterval = 15 * time.Second
+
+// StartPriceRuleScheduler 启动价格规则排期任务
+// 有规则开始或结束时，失效相关商品缓存、记录售价历史、给收藏用户发降价通知
+// 处理进度（水位）保存在 Redis，重启后从上次的水位继续，多实例时只有一个实例执行
+func StartPriceRuleScheduler() {
+	go func() {
+		ticker := time.NewTicker(priceRuleInterval)
+		defer ticker.Stop()
+
+		log.Println("✅ 价格规则排期任务启动")
+
+		adminService.PriceRule.RunScheduledPriceChanges(time.Now()) // 启动时先补齐停机期间的边界
+		for now := range ticker.C {
+			adminService.PriceRule.RunScheduledPriceChanges(now)
+		}
+	}()
+}
@@ -0,0 +1,22 @@
+package consumer
+
+import (
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/service/userService"
+)
+
+// StartReservationSweeper 启动库存预占清扫任务（每分钟释放到期未处理的预占，不依赖 Redis 延迟队列）
+func StartReservationSweeper() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		log.Println("✅ 库存预占清扫任务启动")
+
+		for range ticker.C {
+			userService.Order.SweepExpiredReservations()
+		}
+	}()
+}
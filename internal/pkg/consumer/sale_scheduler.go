@@ -0,0 +1,22 @@
+package consumer
+
+import (
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/service/adminService"
+)
+
+// StartSaleScheduler 启动定时上下架任务（每分钟执行一次到期的上下架并失效缓存）
+func StartSaleScheduler() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		log.Println("✅ 定时上下架任务启动")
+
+		for now := range ticker.C {
+			adminService.Product.ApplySaleSchedules(now)
+		}
+	}()
+}
@@ -6,9 +6,11 @@ import (
 	"log"
 	"time"
 
+	"xiaomi-mall/config"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
 	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/constants"
 
 	"gorm.io/gorm"
 )
@@ -46,8 +48,8 @@ func ConsumeSeckillOrders() {
 
 // 写入数据库（事务）
 func writeSeckillOrderToDB(orderData *types.SeckillOrderQueueData) error {
-	// 1. 查询秒杀商品信息
-	seckillProduct, err := dao.Seckill.GetSeckillProductByID(orderData.SeckillID)
+	// 1. 查询秒杀商品信息（含已删除：删除前已在 Redis 抢到的订单仍要落库，关单时库存归还到 SKU）
+	seckillProduct, err := dao.Seckill.GetSeckillProductUnscoped(orderData.SeckillID)
 	if err != nil {
 		return err
 	}
@@ -85,7 +87,7 @@ func writeSeckillOrderToDB(orderData *types.SeckillOrderQueueData) error {
 			PayStatus:   0,
 			OrderStatus: 0,
 			Type:        2, // 秒杀订单
-			ExpireTime:  time.Now().Add(30 * time.Minute),
+			ExpireTime:  seckillExpireTime(orderData),
 		}
 		if err := tx.Create(order).Error; err != nil {
 			return err
@@ -150,3 +152,12 @@ func moveToDeadLetter(ctx context.Context, orderData *types.SeckillOrderQueueDat
 	log.Printf("🚨 订单入库失败，已投递死信队列: %s", orderData.OrderNum)
 	// TODO: 发送告警
 }
+
+// seckillExpireTime 订单支付截止时间（以下单时写入队列的时间为准，与延迟队列一致）
+func seckillExpireTime(orderData *types.SeckillOrderQueueData) time.Time {
+	if orderData.ExpireTime > 0 {
+		return time.Unix(orderData.ExpireTime, 0)
+	}
+	// 升级前写入队列的旧数据没有截止时间，按下单时间推算
+	return time.Unix(orderData.Timestamp, 0).Add(config.AppConfig.Order.PayTimeout(constants.ORDER_TYPE_SECKILL))
+}
@@ -0,0 +1,115 @@
+package consumer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/dao"
+)
+
+const (
+	stockSyncBatchSize = 500             // 每批扫描的 SKU 数量
+	stockSyncSettle    = 2 * time.Second // 事务提交后同步镜像（IncrSkuStock）的最长耗时，校正前等待在途的同步落地
+)
+
+// StartStockSyncer 启动库存镜像一致性校正（以 MySQL product_skus.stock 为准）
+func StartStockSyncer() {
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute) // 每 5 分钟校正一次
+		defer ticker.Stop()
+
+		log.Println("✅ 库存镜像校正任务启动")
+
+		for range ticker.C {
+			syncSkuStocks()
+		}
+	}()
+}
+
+// syncSkuStocks 分批比对 MySQL 库存和 Redis 镜像，修正不一致的镜像
+// 未缓存的 SKU 跳过（读取时会回源）
+//
+// 镜像在 MySQL 事务提交后才同步，直接用"当前 MySQL 值"覆盖镜像会和在途的同步叠加（增量被算两次），因此：
+//  1. 先读 MySQL（可售库存 + 版本号），等待 stockSyncSettle 让此前已提交事务的镜像同步落地
+//  2. 读取镜像和写入序号，再读一次 MySQL 版本号：版本号变化说明期间有新事务，跳过
+//  3. 按写入序号 CAS：读取镜像之后有任何同步写入都放弃；CAS 之后到达的同步属于第二次读 MySQL 之后提交的事务，叠加在校正值上正好
+func syncSkuStocks() {
+	ctx := context.Background()
+	var lastID uint
+	fixed := 0
+
+	for {
+		skuIDs, err := dao.Product.ScanSkuIDs(lastID, stockSyncBatchSize)
+		if err != nil {
+			log.Printf("❌ 库存镜像校正：查询 SKU 失败: %v", err)
+			return
+		}
+		if len(skuIDs) == 0 {
+			break
+		}
+		lastID = skuIDs[len(skuIDs)-1]
+
+		n, err := syncSkuStockBatch(ctx, skuIDs)
+		if err != nil {
+			log.Printf("❌ 库存镜像校正失败: %v", err)
+			return
+		}
+		fixed += n
+
+		if len(skuIDs) < stockSyncBatchSize {
+			break
+		}
+	}
+
+	if fixed > 0 {
+		log.Printf("⚠️  库存镜像校正：修正 %d 个 SKU", fixed)
+	}
+}
+
+// syncSkuStockBatch 校正一批 SKU，返回修正的数量
+func syncSkuStockBatch(ctx context.Context, skuIDs []uint) (int, error) {
+	// 1️⃣ 第一次读 MySQL，粗略比对镜像，全部一致时不用等待
+	before, err := dao.Product.GetSkuStockVersions(skuIDs)
+	if err != nil {
+		return 0, err
+	}
+	mirror, err := dao.Stock.BatchGetSkuStockSnapshots(ctx, skuIDs)
+	if err != nil {
+		return 0, err
+	}
+	candidates := make([]uint, 0)
+	for skuID, snapshot := range mirror {
+		if db, ok := before[skuID]; ok && db.Available != snapshot.Stock {
+			candidates = append(candidates, skuID)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	// 2️⃣ 等待在途的镜像同步落地，再读镜像和写入序号、第二次读 MySQL 版本号
+	time.Sleep(stockSyncSettle)
+	mirror, err = dao.Stock.BatchGetSkuStockSnapshots(ctx, candidates)
+	if err != nil {
+		return 0, err
+	}
+	after, err := dao.Product.GetSkuStockVersions(candidates)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3️⃣ 期间 MySQL 没有变化且镜像仍不一致时，按写入序号 CAS 修正
+	fixed := 0
+	for _, skuID := range candidates {
+		snapshot, cached := mirror[skuID]
+		db, ok := after[skuID]
+		if !cached || !ok || db.Version != before[skuID].Version || db.Available == snapshot.Stock {
+			continue
+		}
+		if ok, _ := dao.Stock.CompareAndSetSkuStock(ctx, skuID, snapshot.Seq, db.Available); ok {
+			fixed++
+		}
+	}
+	return fixed, nil
+}
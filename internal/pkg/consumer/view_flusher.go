@@ -0,0 +1,52 @@
+package consumer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"xiaomi-mall/internal/dao"
+)
+
+// StartViewFlusher 启动商品点击量批量落库任务
+// 浏览时只写 Redis 计数，这里定时把增量批量写回 products.click_num
+func StartViewFlusher() {
+	go func() {
+		ticker := time.NewTicker(30 * time.Second) // 每 30 秒落库一次
+		defer ticker.Stop()
+
+		log.Println("✅ 点击量落库任务启动")
+
+		for range ticker.C {
+			flushProductViews()
+		}
+	}()
+}
+
+// flushProductViews 取出待落库的点击量，批量更新 MySQL
+// 投递语义为至少一次：Ack 失败时下一轮会再次取到同一批次，由 MySQL 按批次号去重，不会重复计数
+func flushProductViews() {
+	ctx := context.Background()
+
+	batchID, counts, err := dao.View.TakePendingViews(ctx)
+	if err != nil {
+		log.Printf("❌ 读取待落库点击量失败: %v", err)
+		return
+	}
+	if len(counts) == 0 {
+		return
+	}
+
+	if err := dao.Product.BatchIncrementClickNum(batchID, counts); err != nil {
+		// 落库失败，数据保留在 Redis，下一轮重试
+		log.Printf("❌ 点击量落库失败: %v", err)
+		return
+	}
+
+	if err := dao.View.AckPendingViews(ctx); err != nil {
+		// 下一轮会重新取到这一批，按批次号跳过后再确认
+		log.Printf("❌ 点击量落库确认失败（batch=%s）: %v", batchID, err)
+		return
+	}
+	log.Printf("✅ 点击量落库成功：%d 个商品", len(counts))
+}
@@ -0,0 +1,292 @@
+// Package delayqueue 基于 Redis ZSET 的延迟队列（score 为到期时间戳，单位秒）
+//
+// 多实例部署时通过 Lua 脚本原子地"认领"一批到期成员：认领时把成员的 score 改成租约到期时间，
+// 其他实例在租约内看不到它；处理成功后删除，处理失败按指数退避重新排期；
+// 认领后进程崩溃的成员会在租约到期后重新变成到期状态，由其他 worker 接手。
+package delayqueue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"xiaomi-mall/internal/pkg/metrics"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Handler 处理一个到期成员，返回 error 时按退避策略稍后重试
+type Handler func(ctx context.Context, member string) error
+
+// Item 待加入队列的成员及其到期时间
+type Item struct {
+	Member string
+	At     time.Time
+}
+
+// Options 队列参数（零值使用默认值）
+type Options struct {
+	Workers         int           // 并发处理的 worker 数
+	BatchSize       int           // 每次最多认领的成员数（同时不超过空闲 worker 数）
+	PollInterval    time.Duration // 没有到期成员时的轮询间隔
+	Lease           time.Duration // 认领后的租约时长，超过后未确认的成员会被重新认领
+	RetryBackoff    time.Duration // 第一次失败后的重试间隔，之后每次翻倍
+	RetryMaxBackoff time.Duration // 重试间隔上限
+}
+
+// Queue 延迟队列
+type Queue struct {
+	rdb     *redis.Client
+	name    string // 队列名称（用于日志和计数器）
+	key     string // ZSET key
+	handler Handler
+	opts    Options
+
+	processed *metrics.Counter
+	failed    *metrics.Counter
+	claimed   *metrics.Counter
+}
+
+// claimScript 原子认领：取出 score <= now 的前 N 个成员，并把 score 改为租约到期时间
+// KEYS[1] 队列 key；ARGV[1] 当前时间；ARGV[2] 批量大小；ARGV[3] 租约到期时间
+var claimScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(members) do
+	redis.call('ZADD', KEYS[1], 'XX', ARGV[3], member)
+end
+return members
+`)
+
+// ackScript 确认处理成功：成员仍处于本次租约时才删除（租约过期被别人重新认领/业务方重新入队时不动）
+// KEYS[1] 队列 key；KEYS[2] 失败次数 hash；ARGV[1] 成员；ARGV[2] 租约到期时间
+var ackScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if score and tonumber(score) == tonumber(ARGV[2]) then
+	redis.call('ZREM', KEYS[1], ARGV[1])
+	redis.call('HDEL', KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// retryScript 处理失败：失败次数 +1，按 base * 2^(n-1)（不超过 max）重新排期
+// KEYS[1] 队列 key；KEYS[2] 失败次数 hash；ARGV[1] 成员；ARGV[2] 租约到期时间；ARGV[3] 当前时间；ARGV[4] base；ARGV[5] max
+var retryScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) ~= tonumber(ARGV[2]) then
+	return 0
+end
+local attempts = redis.call('HINCRBY', KEYS[2], ARGV[1], 1)
+local delay = tonumber(ARGV[4]) * math.pow(2, attempts - 1)
+if delay > tonumber(ARGV[5]) then
+	delay = tonumber(ARGV[5])
+end
+redis.call('ZADD', KEYS[1], 'XX', tonumber(ARGV[3]) + delay, ARGV[1])
+return attempts
+`)
+
+// New 创建延迟队列（生产者通过 Add / Remove 读写，调用 Start 后开始消费）
+func New(rdb *redis.Client, name, key string) *Queue {
+	q := &Queue{
+		rdb:       rdb,
+		name:      name,
+		key:       key,
+		processed: metrics.NewCounter(fmt.Sprintf("delayqueue_%s_processed_total", name), "延迟队列处理成功次数"),
+		failed:    metrics.NewCounter(fmt.Sprintf("delayqueue_%s_failed_total", name), "延迟队列处理失败次数（失败后按退避重试）"),
+		claimed:   metrics.NewCounter(fmt.Sprintf("delayqueue_%s_claimed_total", name), "延迟队列认领成员次数"),
+	}
+	metrics.NewGauge(fmt.Sprintf("delayqueue_%s_due", name), "已到期但尚未被认领处理的成员数（所有实例共享，-1 表示读取失败）", func() int64 {
+		n, err := q.DueCount(context.Background())
+		if err != nil {
+			return -1
+		}
+		return n
+	})
+	return q
+}
+
+// withDefaults 补齐未设置的队列参数
+func (opts Options) withDefaults() Options {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = 30 * time.Second
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 5 * time.Second
+	}
+	if opts.RetryMaxBackoff < opts.RetryBackoff {
+		opts.RetryMaxBackoff = opts.RetryBackoff
+	}
+	return opts
+}
+
+// Add 加入队列，到 at 时刻到期（已存在的成员会更新到期时间）
+func (q *Queue) Add(ctx context.Context, member string, at time.Time) error {
+	return q.rdb.ZAdd(ctx, q.key, &redis.Z{Score: score(at), Member: member}).Err()
+}
+
+// AddNX 批量加入队列，已存在的成员保持原到期时间，返回新加入的成员数
+func (q *Queue) AddNX(ctx context.Context, items ...Item) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	members := make([]*redis.Z, 0, len(items))
+	for _, item := range items {
+		members = append(members, &redis.Z{Score: score(item.At), Member: item.Member})
+	}
+	return q.rdb.ZAddNX(ctx, q.key, members...).Result()
+}
+
+// Remove 从队列中移除（业务上不再需要处理时调用，同时清除失败次数）
+func (q *Queue) Remove(ctx context.Context, member string) error {
+	pipe := q.rdb.TxPipeline()
+	pipe.ZRem(ctx, q.key, member)
+	pipe.HDel(ctx, q.attemptsKey(), member)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DueCount 已到期但尚未被认领的成员数（已认领的成员 score 为租约到期时间，不计入）
+func (q *Queue) DueCount(ctx context.Context) (int64, error) {
+	return q.rdb.ZCount(ctx, q.key, "-inf", strconv.FormatFloat(score(time.Now()), 'f', -1, 64)).Result()
+}
+
+// Start 启动认领循环和 worker 池（每个实例只调用一次）
+// 每次只认领空闲 worker 数量的成员并立即交给 worker，避免认领后排队等待导致租约过期、被其他实例重复处理
+func (q *Queue) Start(handler Handler, opts Options) {
+	q.handler = handler
+	q.opts = opts.withDefaults()
+
+	jobs := make(chan claim)
+	idle := make(chan struct{}, q.opts.Workers) // 空闲 worker 令牌
+	for i := 0; i < q.opts.Workers; i++ {
+		idle <- struct{}{}
+		go func() {
+			for job := range jobs {
+				q.process(job)
+				idle <- struct{}{}
+			}
+		}()
+	}
+
+	go func() {
+		log.Printf("✅ 延迟队列 %s 启动（worker=%d, batch=%d）", q.name, q.opts.Workers, q.opts.BatchSize)
+		for {
+			// 1️⃣ 等待至少一个空闲 worker，再取走其余空闲令牌（不超过 BatchSize）
+			<-idle
+			free := 1
+			for free < q.opts.BatchSize && len(idle) > 0 {
+				<-idle
+				free++
+			}
+
+			// 2️⃣ 认领不超过空闲 worker 数的成员，多余的令牌归还
+			claims, err := q.claim(context.Background(), free)
+			if err != nil {
+				log.Printf("❌ 延迟队列 %s 认领失败: %v", q.name, err)
+			}
+			for i := len(claims); i < free; i++ {
+				idle <- struct{}{}
+			}
+			for _, c := range claims {
+				jobs <- c
+			}
+
+			// 认领满说明还有积压，等到有空闲 worker 后立即继续；否则等待下一轮
+			if err != nil || len(claims) < free {
+				time.Sleep(q.opts.PollInterval)
+			}
+		}
+	}()
+}
+
+// claim 一次认领的成员及其租约
+type claim struct {
+	member string
+	lease  string // 租约到期时间（与 ZSET 中的 score 一致，确认/重试时用于校验）
+}
+
+// claim 认领最多 limit 个到期成员
+func (q *Queue) claim(ctx context.Context, limit int) ([]claim, error) {
+	now := time.Now()
+	lease := strconv.FormatFloat(score(now.Add(q.opts.Lease)), 'f', 3, 64)
+	members, err := claimScript.Run(ctx, q.rdb, []string{q.key},
+		strconv.FormatFloat(score(now), 'f', 3, 64), limit, lease).StringSlice()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	claims := make([]claim, 0, len(members))
+	for _, member := range members {
+		claims = append(claims, claim{member: member, lease: lease})
+	}
+	q.claimed.Add(int64(len(claims)))
+	return claims, nil
+}
+
+// process 处理一个成员：成功删除，失败退避重试
+func (q *Queue) process(c claim) {
+	ctx := context.Background()
+
+	// 1️⃣ 调用业务处理（panic 视为失败，不影响其他 worker）
+	err := q.safeHandle(ctx, c.member)
+
+	// 2️⃣ 成功：确认删除
+	if err == nil {
+		q.processed.Inc()
+		if _, ackErr := ackScript.Run(ctx, q.rdb, []string{q.key, q.attemptsKey()}, c.member, c.lease).Result(); ackErr != nil {
+			log.Printf("❌ 延迟队列 %s 确认失败：%s, 错误：%v（租约到期后会被重新处理）", q.name, c.member, ackErr)
+		}
+		return
+	}
+
+	// 3️⃣ 失败：退避后重试
+	q.failed.Inc()
+	now := time.Now()
+	attempts, retryErr := retryScript.Run(ctx, q.rdb, []string{q.key, q.attemptsKey()},
+		c.member, c.lease, strconv.FormatFloat(score(now), 'f', 3, 64),
+		q.opts.RetryBackoff.Seconds(), q.opts.RetryMaxBackoff.Seconds()).Int()
+	if retryErr != nil {
+		log.Printf("❌ 延迟队列 %s 处理失败：%s, 错误：%v；重新排期失败：%v（租约到期后会被重新处理）", q.name, c.member, err, retryErr)
+		return
+	}
+	if attempts == 0 {
+		// 租约已过期或业务方已重新入队/移除，交给当前持有者处理
+		log.Printf("❌ 延迟队列 %s 处理失败：%s, 错误：%v（租约已失效，不再重新排期）", q.name, c.member, err)
+		return
+	}
+	log.Printf("❌ 延迟队列 %s 处理失败：%s, 错误：%v（第 %d 次失败，稍后重试）", q.name, c.member, err, attempts)
+}
+
+// safeHandle 调用 handler 并把 panic 转成 error
+func (q *Queue) safeHandle(ctx context.Context, member string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return q.handler(ctx, member)
+}
+
+// attemptsKey 记录每个成员失败次数的 hash
+func (q *Queue) attemptsKey() string {
+	return q.key + ":attempts"
+}
+
+// score 时间转 ZSET score（秒，保留毫秒精度）
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
@@ -0,0 +1,127 @@
+// Package metrics 进程内业务计数器（每个实例单独计数，服务重启后清零）和按需读取的指标，通过管理端接口查看
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter 单调递增计数器，可按标签（如 SKU ID）细分
+type Counter struct {
+	name  string
+	help  string
+	total atomic.Int64
+
+	mu     sync.Mutex
+	labels map[string]*atomic.Int64
+}
+
+// Gauge 瞬时值指标，每次查看时调用 fn 读取当前值（如队列积压数）
+type Gauge struct {
+	name string
+	help string
+	fn   func() int64
+}
+
+// collector 计数器和 Gauge 的统一快照接口
+type collector interface {
+	sample() Sample
+}
+
+// Sample 指标快照
+type Sample struct {
+	Name   string           `json:"name"`
+	Help   string           `json:"help"`
+	Value  int64            `json:"value"`
+	Labels map[string]int64 `json:"labels,omitempty"` // 按标签细分的计数
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]collector)
+)
+
+// NewCounter 注册计数器（同名重复注册返回同一个计数器）
+func NewCounter(name, help string) *Counter {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if c, ok := registry[name].(*Counter); ok {
+		return c
+	}
+	c := &Counter{name: name, help: help, labels: make(map[string]*atomic.Int64)}
+	registry[name] = c
+	return c
+}
+
+// NewGauge 注册 Gauge（同名重复注册时替换读取函数）
+func NewGauge(name, help string, fn func() int64) *Gauge {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	g := &Gauge{name: name, help: help, fn: fn}
+	registry[name] = g
+	return g
+}
+
+// Inc 计数 +1
+func (c *Counter) Inc() {
+	c.total.Add(1)
+}
+
+// Add 计数 +n
+func (c *Counter) Add(n int64) {
+	c.total.Add(n)
+}
+
+// IncLabel 计数 +1，同时累加到指定标签
+func (c *Counter) IncLabel(label string) {
+	c.total.Add(1)
+
+	c.mu.Lock()
+	v, ok := c.labels[label]
+	if !ok {
+		v = new(atomic.Int64)
+		c.labels[label] = v
+	}
+	c.mu.Unlock()
+	v.Add(1)
+}
+
+// sample 计数器快照
+func (c *Counter) sample() Sample {
+	sample := Sample{Name: c.name, Help: c.help, Value: c.total.Load()}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.labels) > 0 {
+		sample.Labels = make(map[string]int64, len(c.labels))
+		for label, v := range c.labels {
+			sample.Labels[label] = v.Load()
+		}
+	}
+	return sample
+}
+
+// sample Gauge 快照（读取失败时由 fn 自行返回约定值）
+func (g *Gauge) sample() Sample {
+	return Sample{Name: g.name, Help: g.help, Value: g.fn()}
+}
+
+// Snapshot 所有指标的当前值（按名称排序），prefix 非空时只返回该前缀的指标
+func Snapshot(prefix string) []Sample {
+	registryMu.RLock()
+	collectors := make([]collector, 0, len(registry))
+	for name, c := range registry {
+		if strings.HasPrefix(name, prefix) {
+			collectors = append(collectors, c)
+		}
+	}
+	registryMu.RUnlock()
+
+	samples := make([]Sample, 0, len(collectors))
+	for _, c := range collectors {
+		samples = append(samples, c.sample())
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Name < samples[j].Name })
+	return samples
+}
@@ -0,0 +1,106 @@
+package orderevent
+
+import (
+	"log"
+	"sync"
+
+	"xiaomi-mall/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ============ 订单状态变更事件 ============
+// 订单状态流转时统一在这里发布事件，销量、评价等副作用注册为处理器，
+// 避免在各个下单/支付/退款流程里散落 SQL
+//
+// 两类处理器：
+//   - OnTx：在订单状态变更的同一个事务内执行，返回错误会导致整个事务回滚（适合需要强一致的计数）
+//   - On  ：事务提交后异步执行，失败只记录日志（适合缓存、通知等可丢失的副作用）
+
+// Kind 事件类型
+type Kind int
+
+const (
+	Paid      Kind = iota + 1 // 已支付
+	Refunded                  // 已退款
+	Cancelled                 // 已取消（超时关闭 / 用户取消）
+	Shipped                   // 已发货
+	Completed                 // 已完成（确认收货）
+)
+
+// Event 订单事件
+type Event struct {
+	Kind      Kind
+	OrderNum  string
+	UserID    uint
+	OrderType int                // 1:普通订单 2:秒杀订单
+	Items     []*model.OrderItem // 订单商品快照
+}
+
+// New 构造订单事件
+func New(kind Kind, order *model.Order, items []*model.OrderItem) *Event {
+	return &Event{
+		Kind:      kind,
+		OrderNum:  order.OrderNum,
+		UserID:    order.UserID,
+		OrderType: order.Type,
+		Items:     items,
+	}
+}
+
+type (
+	TxHandler    func(tx *gorm.DB, e *Event) error
+	AsyncHandler func(e *Event)
+)
+
+var (
+	mu            sync.RWMutex
+	txHandlers    = make(map[Kind][]TxHandler)
+	asyncHandlers = make(map[Kind][]AsyncHandler)
+)
+
+// OnTx 注册事务内处理器
+func OnTx(kind Kind, h TxHandler) {
+	mu.Lock()
+	defer mu.Unlock()
+	txHandlers[kind] = append(txHandlers[kind], h)
+}
+
+// On 注册事务提交后的异步处理器
+func On(kind Kind, h AsyncHandler) {
+	mu.Lock()
+	defer mu.Unlock()
+	asyncHandlers[kind] = append(asyncHandlers[kind], h)
+}
+
+// PublishTx 在事务内执行处理器（必须在订单状态更新成功后、事务提交前调用）
+func PublishTx(tx *gorm.DB, e *Event) error {
+	mu.RLock()
+	handlers := txHandlers[e.Kind]
+	mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(tx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish 事务提交后调用，异步执行处理器
+func Publish(e *Event) {
+	mu.RLock()
+	handlers := asyncHandlers[e.Kind]
+	mu.RUnlock()
+
+	for _, h := range handlers {
+		go func(h AsyncHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("❌ 订单事件处理器 panic: kind=%d order=%s err=%v", e.Kind, e.OrderNum, r)
+				}
+			}()
+			h(e)
+		}(h)
+	}
+}
@@ -0,0 +1,122 @@
+// Package pricing SKU 实际售价的唯一计算入口
+//
+// 实际售价 = 当前生效的价格规则价（PriceRule），没有生效规则时为 SKU 原价（ProductSku.Price）。
+// 商品详情、SKU 详情、下单都通过这里取价，售价变化时追加 PriceHistory 记录。
+package pricing
+
+import (
+	"time"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+)
+
+// Quote SKU 在某一时刻的报价
+type Quote struct {
+	SkuID     uint
+	ProductID uint
+	BasePrice int64      // SKU 原价
+	Price     int64      // 实际售价
+	RuleID    uint       // 生效的价格规则（0 表示按原价）
+	EndTime   *time.Time // 规则价的结束时间
+}
+
+// Change 一次售价变化（RecordChanges 返回，用于降价通知等后续处理）
+type Change struct {
+	SkuID     uint
+	ProductID uint
+	OldPrice  int64
+	NewPrice  int64
+}
+
+// ResolveSkus 计算 SKU 在 at 时刻的实际售价（key 为 SKU ID）
+func ResolveSkus(skus []*model.ProductSku, at time.Time) (map[uint]Quote, error) {
+	skuIDs := make([]uint, 0, len(skus))
+	for _, sku := range skus {
+		skuIDs = append(skuIDs, sku.ID)
+	}
+	rules, err := dao.Price.GetEffectiveRules(skuIDs, at)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[uint]Quote, len(skus))
+	for _, sku := range skus {
+		quote := Quote{
+			SkuID:     sku.ID,
+			ProductID: sku.ProductID,
+			BasePrice: sku.Price,
+			Price:     sku.Price,
+		}
+		if rule, ok := rules[sku.ID]; ok {
+			endTime := rule.EndTime
+			quote.Price = rule.Price
+			quote.RuleID = rule.ID
+			quote.EndTime = &endTime
+		}
+		quotes[sku.ID] = quote
+	}
+	return quotes, nil
+}
+
+// RecordChanges 计算 SKU 当前的实际售价，与当前生效的历史记录不同时追加记录
+// 返回售价发生变化的 SKU（首次记录的 SKU 没有旧价格，不算变化）
+func RecordChanges(skuIDs []uint, source string) ([]Change, error) {
+	return RecordChangesAt(skuIDs, source, time.Now())
+}
+
+// RecordChangesAt 同 RecordChanges，按 at 时刻计算售价并以 at 作为生效时间
+// 规则开始/结束时传入规则的开始/结束时刻，保证"at 时刻客户看到的价格"可以从历史中准确查到；
+// 与 at 时刻已生效的记录相同时跳过，重复调用不会产生重复记录
+func RecordChangesAt(skuIDs []uint, source string, at time.Time) ([]Change, error) {
+	if len(skuIDs) == 0 {
+		return nil, nil
+	}
+
+	// 1️⃣ at 时刻的售价
+	skus, err := dao.Product.GetSkusByIDs(skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := ResolveSkus(skus, at)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2️⃣ 与 at 时刻生效的记录比较
+	latest, err := dao.Price.GetHistoriesAt(skuIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	var histories []*model.PriceHistory
+	var changes []Change
+	for _, sku := range skus {
+		quote := quotes[sku.ID]
+		prev, ok := latest[sku.ID]
+		if ok && prev.Price == quote.Price && prev.RuleID == quote.RuleID {
+			continue
+		}
+		histories = append(histories, &model.PriceHistory{
+			ProductID:    quote.ProductID,
+			ProductSkuID: quote.SkuID,
+			Price:        quote.Price,
+			BasePrice:    quote.BasePrice,
+			RuleID:       quote.RuleID,
+			Source:       source,
+			EffectiveAt:  at,
+		})
+		if ok && prev.Price != quote.Price {
+			changes = append(changes, Change{
+				SkuID:     quote.SkuID,
+				ProductID: quote.ProductID,
+				OldPrice:  prev.Price,
+				NewPrice:  quote.Price,
+			})
+		}
+	}
+
+	// 3️⃣ 追加记录
+	if err := dao.Price.CreateHistories(histories); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,91 @@
+package recommend
+
+import (
+	"context"
+	"log"
+
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+)
+
+// 每批扫描的订单数
+const scanBatchSize = 500
+
+// Rebuild 全量重建「买了又买」关联商品
+// 扫描所有已支付订单，统计商品两两之间的共同购买次数，覆盖写入 Redis
+// 返回写入的商品数量
+func Rebuild(ctx context.Context) (int, error) {
+	// 1️⃣ 分批扫描已支付订单，在内存中累加共同购买次数
+	coCounts := make(map[uint]map[uint]int64)
+	var lastID uint
+	scanned := 0
+
+	for {
+		orders, err := dao.Recommend.ScanPaidOrders(lastID, scanBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(orders) == 0 {
+			break
+		}
+		lastID = orders[len(orders)-1].ID
+		scanned += len(orders)
+
+		orderNums := make([]string, 0, len(orders))
+		for _, order := range orders {
+			orderNums = append(orderNums, order.OrderNum)
+		}
+		items, err := dao.Recommend.GetOrderProducts(orderNums)
+		if err != nil {
+			return 0, err
+		}
+
+		// 按订单分组
+		byOrder := make(map[string][]*model.OrderItem, len(orders))
+		for _, item := range items {
+			byOrder[item.OrderNum] = append(byOrder[item.OrderNum], item)
+		}
+
+		for _, orderItems := range byOrder {
+			productIDs := DistinctProductIDs(orderItems)
+			for _, a := range productIDs {
+				for _, b := range productIDs {
+					if a == b {
+						continue
+					}
+					if coCounts[a] == nil {
+						coCounts[a] = make(map[uint]int64)
+					}
+					coCounts[a][b]++
+				}
+			}
+		}
+
+		if len(orders) < scanBatchSize {
+			break
+		}
+	}
+
+	// 2️⃣ 写入 Redis（每个商品只保留 Top N）
+	for productID, scores := range coCounts {
+		if err := dao.Recommend.ReplaceRelated(ctx, productID, scores); err != nil {
+			return 0, err
+		}
+	}
+
+	log.Printf("✅ 关联推荐重建完成：扫描 %d 个订单，写入 %d 个商品", scanned, len(coCounts))
+	return len(coCounts), nil
+}
+
+// DistinctProductIDs 订单中去重后的商品 ID（同一商品的多个 SKU 只算一次）
+func DistinctProductIDs(items []*model.OrderItem) []uint {
+	seen := make(map[uint]bool, len(items))
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			ids = append(ids, item.ProductID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,135 @@
+// Package sheet 表格文件读写（CSV / XLSX），用于批量导入导出
+//
+// XLSX 只实现导入导出需要的最小子集：读取第一个工作表的单元格文本，写出单个纯文本工作表，
+// 不处理公式、样式、合并单元格和日期格式。
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// 支持的文件格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// maxColumns 最多读取的列数（超出时视为无效文件，避免按单元格引用分配过大的行）
+const maxColumns = 1024
+
+var (
+	ErrUnsupportedFormat = errors.New("sheet: 不支持的文件格式")
+	ErrTooManyRows       = errors.New("sheet: 行数超出上限")
+)
+
+// FormatOf 按文件名后缀判断格式（无法识别时返回空字符串）
+func FormatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	}
+	return ""
+}
+
+// ContentType 格式对应的 MIME 类型
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ReadAll 读取表格的全部行（包含表头），每个单元格去掉首尾空白，尾部空行会被丢弃
+// maxRows 为最多读取的行数（包含表头），超出时返回 ErrTooManyRows
+func ReadAll(data []byte, format string, maxRows int) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(data)
+	case FormatXLSX:
+		rows, err = readXLSX(data, maxRows)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		for j := range row {
+			rows[i][j] = strings.TrimSpace(row[j])
+		}
+	}
+	for len(rows) > 0 && isBlankRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) > maxRows {
+		return nil, ErrTooManyRows
+	}
+	return rows, nil
+}
+
+// Writer 逐行写出表格，写完后必须调用 Close
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewWriter 创建指定格式的 Writer
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// isBlankRow 判断一行是否全部为空
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ============ CSV ============
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel 另存的 CSV 带 UTF-8 BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1 // 允许每行列数不同
+	r.LazyQuotes = true
+	return r.ReadAll()
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	w.Write([]byte("\xef\xbb\xbf")) // 写 BOM，Excel 打开中文不乱码
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,88 @@
+package stockalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Alert 低库存告警内容
+type Alert struct {
+	AlertID     uint      `json:"alert_id"`
+	ProductID   uint      `json:"product_id"`
+	SkuID       uint      `json:"sku_id"`
+	ProductName string    `json:"product_name"`
+	SkuTitle    string    `json:"sku_title"`
+	Available   int       `json:"available"` // 可售库存
+	Threshold   int       `json:"threshold"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Notifier 告警通知渠道
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// LogNotifier 打印日志
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string { return "log" }
+
+func (LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	log.Printf("⚠️  低库存告警：%s - %s（SKU %d）可售库存 %d，阈值 %d",
+		alert.ProductName, alert.SkuTitle, alert.SkuID, alert.Available, alert.Threshold)
+	return nil
+}
+
+// WebhookNotifier POST JSON 到指定地址（如企业微信 / 钉钉机器人的中转服务）
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailNotifier 邮件通知（占位实现：尚未接入邮件服务，只打印将要发送的内容）
+type EmailNotifier struct {
+	To []string
+}
+
+func (n *EmailNotifier) Name() string { return "email" }
+
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	log.Printf("📧 [邮件] 收件人 %s：【低库存】%s - %s 可售库存 %d（阈值 %d），请及时补货",
+		strings.Join(n.To, ","), alert.ProductName, alert.SkuTitle, alert.Available, alert.Threshold)
+	return nil
+}
@@ -0,0 +1,312 @@
+package stockalert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/constants"
+)
+
+// ============ 库存变化后的检查 ============
+// 可售库存（stock - reserved）变化后调用 Apply / Changed，只有跨过阈值或 0 时才检查：
+//   1. 低于等于阈值时创建告警（同一 SKU 不重复）并通知运营，回到阈值以上时恢复告警
+//   2. 可售库存大于 0 时给等待到货的订阅用户发送站内通知
+// 检查由固定数量的 worker 异步执行，队列满时丢弃并打印日志（低库存列表和下次跨越时会再次检查）
+
+var notifiers []Notifier
+
+// Init 根据配置注册告警通知渠道
+func Init(cfg config.StockConfig) {
+	notifiers = notifiers[:0]
+	for _, name := range cfg.AlertNotifiers {
+		switch name {
+		case "log":
+			notifiers = append(notifiers, LogNotifier{})
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				log.Println("⚠️  低库存告警：未配置 stock.webhook_url，跳过 webhook 通知")
+				continue
+			}
+			notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL))
+		case "email":
+			notifiers = append(notifiers, &EmailNotifier{To: cfg.AlertEmails})
+		default:
+			log.Printf("⚠️  低库存告警：未知的通知方式 %q", name)
+		}
+	}
+}
+
+// Register 追加自定义通知渠道
+func Register(n Notifier) {
+	notifiers = append(notifiers, n)
+}
+
+const (
+	checkWorkers   = 4
+	checkQueueSize = 1024
+	thresholdTTL   = time.Minute // 阈值缓存时长，其他实例修改阈值后最多延迟这么久生效
+
+	unknownStock = -1 // 变化前后的可售库存未知
+)
+
+var (
+	pending   = make(chan Change, checkQueueSize)
+	startOnce sync.Once
+
+	thresholds sync.Map // skuID -> cachedThreshold
+)
+
+type cachedThreshold struct {
+	value    int
+	loadedAt time.Time
+}
+
+// Change 一个 SKU 的可售库存变化
+type Change struct {
+	SkuID  uint
+	Before int // 变化前的可售库存
+	After  int // 变化后的可售库存
+}
+
+// Apply 同步库存镜像（镜像为可售库存，按增减量同步），并按变化前后的可售库存触发检查
+// 镜像不存在时无法得知变化前后的值，直接检查
+func Apply(ctx context.Context, deltas map[uint]int) {
+	changes := make([]Change, 0, len(deltas))
+	for skuID, delta := range deltas {
+		after, ok, err := dao.Stock.IncrSkuStock(ctx, skuID, delta)
+		if err != nil || !ok {
+			Check(skuID)
+			continue
+		}
+		changes = append(changes, Change{SkuID: skuID, Before: after - delta, After: after})
+	}
+	Changed(changes...)
+}
+
+// Changed 可售库存发生变化（库存事务提交后调用，异步检查，不影响主流程）
+func Changed(changes ...Change) {
+	for _, change := range changes {
+		if change.Before == change.After {
+			continue
+		}
+		enqueue(change)
+	}
+}
+
+// Check 无法得知变化前后的值时（如批量导入）直接异步检查
+func Check(skuIDs ...uint) {
+	for _, skuID := range skuIDs {
+		enqueue(Change{SkuID: skuID, Before: unknownStock, After: unknownStock})
+	}
+}
+
+// CheckNow 同步检查（管理端修改阈值后立即生效）
+func CheckNow(skuIDs ...uint) {
+	for _, skuID := range skuIDs {
+		thresholds.Delete(skuID)
+	}
+	check(skuIDs)
+}
+
+// enqueue 交给 worker 检查（队列满时丢弃，不阻塞下单等主流程）
+func enqueue(change Change) {
+	startOnce.Do(startWorkers)
+	select {
+	case pending <- change:
+	default:
+		log.Printf("⚠️  库存检查：队列已满，丢弃 sku=%d（%d -> %d）", change.SkuID, change.Before, change.After)
+	}
+}
+
+// startWorkers 启动固定数量的检查 worker
+func startWorkers() {
+	for i := 0; i < checkWorkers; i++ {
+		go func() {
+			for change := range pending {
+				if !shouldCheck(change) {
+					continue
+				}
+				check([]uint{change.SkuID})
+			}
+		}()
+	}
+}
+
+// shouldCheck 可售库存跨过阈值或 0 时才需要检查（变化前后未知时直接检查）
+func shouldCheck(change Change) bool {
+	if change.Before == unknownStock && change.After == unknownStock {
+		return true
+	}
+	if crossed(change, 0) {
+		return true
+	}
+	threshold, err := thresholdOf(change.SkuID)
+	if err != nil {
+		log.Printf("❌ 库存检查：查询阈值失败: sku=%d, 错误: %v", change.SkuID, err)
+		return true
+	}
+	return threshold > 0 && crossed(change, threshold)
+}
+
+// crossed 变化前后是否分别位于 level 的两侧（<= level 视为低于）
+func crossed(change Change, level int) bool {
+	return (change.Before > level) != (change.After > level)
+}
+
+// thresholdOf 读取 SKU 低库存阈值（本地缓存 thresholdTTL）
+func thresholdOf(skuID uint) (int, error) {
+	if v, ok := thresholds.Load(skuID); ok {
+		cached := v.(cachedThreshold)
+		if time.Since(cached.loadedAt) < thresholdTTL {
+			return cached.value, nil
+		}
+	}
+	values, err := dao.Product.GetSkuThresholds([]uint{skuID})
+	if err != nil {
+		return 0, err
+	}
+	value := values[skuID]
+	thresholds.Store(skuID, cachedThreshold{value: value, loadedAt: time.Now()})
+	return value, nil
+}
+
+func check(skuIDs []uint) {
+	skus, err := dao.Product.GetSkusByIDs(skuIDs)
+	if err != nil {
+		log.Printf("❌ 库存检查：查询 SKU 失败: %v, 错误: %v", skuIDs, err)
+		return
+	}
+	if len(skus) == 0 {
+		return
+	}
+	productIDs := make([]uint, 0, len(skus))
+	for _, sku := range skus {
+		productIDs = append(productIDs, sku.ProductID)
+	}
+	products, err := dao.Product.GetProductsByIDs(productIDs)
+	if err != nil {
+		log.Printf("❌ 库存检查：查询商品失败: %v, 错误: %v", productIDs, err)
+		return
+	}
+
+	resolveSkuIDs := make([]uint, 0, len(skus))
+	for _, sku := range skus {
+		if sku.LowStockThreshold <= 0 || sku.Stock-sku.Reserved > sku.LowStockThreshold {
+			resolveSkuIDs = append(resolveSkuIDs, sku.ID)
+		}
+	}
+	// 只恢复确实有未恢复告警的 SKU
+	openAlerts := map[uint]bool{}
+	if len(resolveSkuIDs) > 0 {
+		if openAlerts, err = dao.StockAlert.GetOpenAlertSkuIDs(resolveSkuIDs); err != nil {
+			log.Printf("❌ 库存检查：查询未恢复告警失败: %v, 错误: %v", resolveSkuIDs, err)
+			return
+		}
+	}
+
+	for _, sku := range skus {
+		name := ""
+		if product, ok := products[sku.ProductID]; ok {
+			name = product.Name
+		}
+		available := sku.Stock - sku.Reserved
+
+		// 1️⃣ 低库存告警
+		if sku.LowStockThreshold > 0 && available <= sku.LowStockThreshold {
+			openAlert(sku, name, available)
+		} else if openAlerts[sku.ID] {
+			if err := dao.StockAlert.ResolveAlert(sku.ID); err != nil {
+				log.Printf("❌ 库存检查：恢复告警失败: sku=%d, 错误: %v", sku.ID, err)
+			}
+		}
+
+		// 2️⃣ 到货提醒
+		if available > 0 {
+			notifyBackInStock(sku, name)
+		}
+	}
+}
+
+// openAlert 创建告警记录，首次创建时通知运营
+func openAlert(sku *model.ProductSku, productName string, available int) {
+	alert := &model.StockAlert{
+		ProductID:    sku.ProductID,
+		ProductSkuID: sku.ID,
+		Available:    available,
+		Threshold:    sku.LowStockThreshold,
+	}
+	opened, err := dao.StockAlert.OpenAlert(alert)
+	if err != nil {
+		log.Printf("❌ 低库存告警：写入告警失败: sku=%d, 错误: %v", sku.ID, err)
+		return
+	}
+	if !opened {
+		return // 已有未恢复的告警
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, Alert{
+			AlertID:     alert.ID,
+			ProductID:   sku.ProductID,
+			SkuID:       sku.ID,
+			ProductName: productName,
+			SkuTitle:    sku.Title,
+			Available:   available,
+			Threshold:   sku.LowStockThreshold,
+			CreatedAt:   alert.CreatedAt,
+		}); err != nil {
+			log.Printf("❌ 低库存告警：%s 通知失败: sku=%d, 错误: %v", n.Name(), sku.ID, err)
+		}
+	}
+}
+
+// notifyBackInStock 给等待到货的订阅用户写入站内通知（每个订阅只通知一次）
+func notifyBackInStock(sku *model.ProductSku, productName string) {
+	subscriptions, err := dao.StockAlert.GetWaitingSubscriptions(sku.ID)
+	if err != nil {
+		log.Printf("❌ 到货提醒：查询订阅失败: sku=%d, 错误: %v", sku.ID, err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	name := productName + " - " + sku.Title
+	payload, _ := json.Marshal(types.BackInStockPayload{
+		ProductID:   sku.ProductID,
+		SkuID:       sku.ID,
+		ProductName: name,
+	})
+	notifications := make([]*model.Notification, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		marked, err := dao.StockAlert.MarkNotified(subscription.ID)
+		if err != nil || !marked {
+			continue // 已被并发的检查通知过
+		}
+		notifications = append(notifications, &model.Notification{
+			UserID:  subscription.UserID,
+			Type:    constants.NOTIFICATION_BACK_IN_STOCK,
+			Title:   "订阅的商品到货啦",
+			Content: fmt.Sprintf("您订阅的「%s」已到货，快去看看吧", name),
+			Payload: string(payload),
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+	if err := dao.Notification.BatchCreate(notifications); err != nil {
+		log.Printf("❌ 到货提醒：写入通知失败: sku=%d, 错误: %v", sku.ID, err)
+		return
+	}
+	log.Printf("✅ 到货提醒：sku=%d 通知 %d 个用户", sku.ID, len(notifications))
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xiaomi-mall/config"
+)
+
+// ============ 文件存储 ============
+// 业务层只依赖 Storage 接口，通过配置 oss.driver 切换本地磁盘 / S3 兼容对象存储
+
+// Storage 文件存储后端
+type Storage interface {
+	// Put 写入对象（key 已存在时覆盖）
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Exists 判断对象是否存在（用于按内容哈希去重）
+	Exists(ctx context.Context, key string) (bool, error)
+	// URL 对象的公开访问地址
+	URL(key string) string
+}
+
+// Presigner 支持客户端直传的存储后端（预签名 PUT）
+type Presigner interface {
+	// PresignPut 生成预签名上传地址，客户端必须原样携带返回的请求头
+	PresignPut(ctx context.Context, key string, opts PresignOptions) (*PresignedRequest, error)
+}
+
+// PresignOptions 预签名参数（签入 URL，上传时由存储服务校验）
+type PresignOptions struct {
+	ContentType   string
+	ContentLength int64
+	SHA256        []byte // 文件内容的 SHA-256，存储服务会校验，防止内容和 key 不一致
+	Expires       time.Duration
+}
+
+// PresignedRequest 预签名上传请求
+type PresignedRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+}
+
+// Default 全局存储后端（Init 后可用）
+var Default Storage
+
+// Init 根据配置初始化存储后端
+func Init(cfg config.OSSConfig) error {
+	switch cfg.Driver {
+	case "", "local":
+		local, err := NewLocalStorage(cfg.LocalDir, cfg.LocalURLPrefix)
+		if err != nil {
+			return err
+		}
+		Default = local
+	case "s3":
+		s3, err := NewS3Storage(cfg)
+		if err != nil {
+			return err
+		}
+		Default = s3
+	default:
+		return fmt.Errorf("不支持的存储后端: %s", cfg.Driver)
+	}
+	return nil
+}
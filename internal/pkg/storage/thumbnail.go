@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// Thumbnail 按最长边等比缩小（区域平均采样），透明背景填充为白色，输出 JPEG
+// 原图小于 maxSide 时不放大
+func Thumbnail(src image.Image, maxSide int, quality int) ([]byte, error) {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > maxSide || srcH > maxSide {
+		if srcW >= srcH {
+			dstW = maxSide
+			dstH = max(1, srcH*maxSide/srcW)
+		} else {
+			dstH = maxSide
+			dstW = max(1, srcW*maxSide/srcH)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		// 目标像素对应的源区域 [y0, y1)
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+			dst.SetRGBA(x, y, averageOnWhite(src, x0, y0, x1, y1))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// averageOnWhite 计算区域平均颜色（预乘 alpha 后叠加在白色背景上）
+func averageOnWhite(src image.Image, x0, y0, x1, y1 int) color.RGBA {
+	var r, g, b, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			pr, pg, pb, pa := src.At(x, y).RGBA() // 16 位预乘 alpha
+			white := 0xffff - uint64(pa)
+			r += uint64(pr) + white
+			g += uint64(pg) + white
+			b += uint64(pb) + white
+			n++
+		}
+	}
+	return color.RGBA{
+		R: uint8(r / n >> 8),
+		G: uint8(g / n >> 8),
+		B: uint8(b / n >> 8),
+		A: 0xff,
+	}
+}
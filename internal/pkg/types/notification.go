@@ -0,0 +1,26 @@
+package types
+
+import "time"
+
+// PriceDropPayload 收藏商品降价通知的业务数据（Notification.Payload）
+type PriceDropPayload struct {
+	ProductID   uint   `json:"product_id"`
+	SkuID       uint   `json:"sku_id,omitempty"` // 0 表示商品展示价 / 折扣价变化
+	ProductName string `json:"product_name"`
+	OldPrice    int64  `json:"old_price"` // 单位：分
+	NewPrice    int64  `json:"new_price"` // 单位：分
+}
+
+// BackInStockPayload 到货提醒通知的业务数据（Notification.Payload）
+type BackInStockPayload struct {
+	ProductID   uint   `json:"product_id"`
+	SkuID       uint   `json:"sku_id"`
+	ProductName string `json:"product_name"`
+}
+
+// PayReminderPayload 待支付提醒通知的业务数据（Notification.Payload）
+type PayReminderPayload struct {
+	OrderNum   string    `json:"order_num"`
+	AllPrice   int64     `json:"all_price"`   // 单位：分
+	ExpireTime time.Time `json:"expire_time"` // 支付截止时间
+}
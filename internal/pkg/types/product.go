@@ -0,0 +1,17 @@
+package types
+
+// 图文详情块类型
+const (
+	ContentBlockText    = "text"    // 段落文本（纯文本，客户端按文本渲染，不解析 HTML）
+	ContentBlockHeading = "heading" // 小标题
+	ContentBlockImage   = "image"   // 图片
+)
+
+// ContentBlock 商品图文详情中的一个内容块（ProductContent.Blocks 为块数组的 JSON）
+type ContentBlock struct {
+	Type   string `json:"type" binding:"required,oneof=text heading image"`
+	Text   string `json:"text,omitempty" binding:"max=5000"` // text / heading 的文字内容
+	URL    string `json:"url,omitempty" binding:"max=500"`   // image 的图片地址
+	Width  int    `json:"width,omitempty" binding:"min=0"`   // 图片宽度（可选，用于客户端占位）
+	Height int    `json:"height,omitempty" binding:"min=0"`  // 图片高度（可选）
+}
@@ -7,6 +7,7 @@ type SeckillOrderQueueData struct {
 	SeckillID    uint   `json:"seckill_id"`
 	OrderNum     string `json:"order_num"`
 	Timestamp    int64  `json:"timestamp"`
+	ExpireTime   int64  `json:"expire_time"`    // 支付截止时间（Unix 秒，与延迟队列中的到期时间一致）
 	RetryCount   int    `json:"retry_count"`    // 重试次数
 	FirstTryTime int64  `json:"first_try_time"` // 首次尝试时间
 	LastTryTime  int64  `json:"last_try_time"`  // 最后尝试时间
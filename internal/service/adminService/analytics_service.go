@@ -0,0 +1,63 @@
+package adminService
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type AnalyticsService struct{}
+
+var Analytics = new(AnalyticsService)
+
+// 匿名浏览路径（基于用户浏览历史，用户 ID 做不可逆摘要）
+func (s *AnalyticsService) ViewPaths(req dto.ViewPathsReq) (*vo.ViewPathsResp, error) {
+	// 未配置匿名化密钥时不返回数据，避免使用可预测的摘要
+	salt := config.AppConfig.Analytics.Salt
+	if salt == "" {
+		return nil, xerr.NewErrMsg("未配置 analytics.salt，无法生成匿名访客 ID")
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = 100
+	}
+
+	paths, next, err := dao.History.ScanHistories(ctx, req.Cursor, count)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	list := make([]vo.ViewPathVO, 0, len(paths))
+	for userID, entries := range paths {
+		steps := make([]vo.ViewStepVO, 0, len(entries))
+		for _, entry := range entries {
+			steps = append(steps, vo.ViewStepVO{
+				ProductID: entry.ProductID,
+				ViewedAt:  entry.ViewedAt,
+			})
+		}
+		list = append(list, vo.ViewPathVO{
+			Visitor: anonymizeUserID(salt, userID),
+			Steps:   steps,
+		})
+	}
+
+	return &vo.ViewPathsResp{
+		List:       list,
+		NextCursor: next,
+	}, nil
+}
+
+// anonymizeUserID 用户 ID 的 HMAC-SHA256 摘要（以 analytics.salt 为 key，无法通过枚举 ID 反推）
+func anonymizeUserID(salt string, userID uint) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(strconv.FormatUint(uint64(userID), 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
@@ -0,0 +1,190 @@
+package adminService
+
+import (
+	"time"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/pkg/constants"
+	parseTime "xiaomi-mall/pkg/parsetime"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type CarouselService struct{}
+
+var Carousel = new(CarouselService)
+
+// 创建运营位
+func (s *CarouselService) CreateCarousel(req dto.CreateCarouselReq) (*vo.CarouselVO, error) {
+	// 1️⃣ 校验参数
+	startTime, endTime, err := validateCarousel(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2️⃣ 写入数据库（新建默认启用）
+	carousel := &model.Carousel{
+		Slot:           req.Slot,
+		SlotCategoryID: req.SlotCategoryID,
+		Title:          req.Title,
+		ImgPath:        req.ImgPath,
+		TargetType:     req.TargetType,
+		ProductID:      req.ProductID,
+		CategoryID:     req.CategoryID,
+		URL:            req.URL,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		Sort:           req.Sort,
+		Status:         constants.CAROUSEL_STATUS_ENABLED,
+	}
+	if err := dao.Carousel.CreateCarousel(carousel); err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 3️⃣ 失效首页缓存
+	cache.Invalidate(ctx, cache.HomeKey)
+
+	resp := toCarouselVO(carousel)
+	return &resp, nil
+}
+
+// 更新运营位（全量更新）
+func (s *CarouselService) UpdateCarousel(req dto.UpdateCarouselReq) error {
+	if _, err := dao.Carousel.GetCarouselByID(req.ID); err != nil {
+		return xerr.NewErrMsg("运营位不存在")
+	}
+
+	startTime, endTime, err := validateCarousel(req.CreateCarouselReq)
+	if err != nil {
+		return err
+	}
+
+	// 已过期的运营位延长结束时间后，按传入状态重新生效
+	err = dao.Carousel.UpdateCarousel(req.ID, map[string]interface{}{
+		"slot":             req.Slot,
+		"slot_category_id": req.SlotCategoryID,
+		"title":            req.Title,
+		"img_path":         req.ImgPath,
+		"target_type":      req.TargetType,
+		"product_id":       req.ProductID,
+		"category_id":      req.CategoryID,
+		"url":              req.URL,
+		"start_time":       startTime,
+		"end_time":         endTime,
+		"sort":             req.Sort,
+		"status":           req.Status,
+	})
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	cache.Invalidate(ctx, cache.HomeKey)
+	return nil
+}
+
+// 删除运营位
+func (s *CarouselService) DeleteCarousel(req dto.CarouselIDReq) error {
+	if _, err := dao.Carousel.GetCarouselByID(req.ID); err != nil {
+		return xerr.NewErrMsg("运营位不存在")
+	}
+	if err := dao.Carousel.DeleteCarousel(req.ID); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	cache.Invalidate(ctx, cache.HomeKey)
+	return nil
+}
+
+// 运营位列表
+func (s *CarouselService) CarouselList(req dto.CarouselListReq) (*vo.CarouselListResp, error) {
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	carousels, total, err := dao.Carousel.GetCarouselList(req.Slot, req.Status, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.CarouselVO, 0, len(carousels))
+	for _, carousel := range carousels {
+		list = append(list, toCarouselVO(carousel))
+	}
+
+	return &vo.CarouselListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// validateCarousel 校验运营位参数，返回解析后的展示时间窗口
+func validateCarousel(req dto.CreateCarouselReq) (time.Time, time.Time, error) {
+	// 1. 展示时间
+	startTime, err := parseTime.ParseDateTimeStr(req.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, xerr.NewErrMsg("开始时间格式错误")
+	}
+	endTime, err := parseTime.ParseDateTimeStr(req.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, xerr.NewErrMsg("结束时间格式错误")
+	}
+	if !endTime.After(startTime) {
+		return time.Time{}, time.Time{}, xerr.NewErrMsg("结束时间必须晚于开始时间")
+	}
+
+	// 2. 分类精选必须指定分类
+	if req.Slot == constants.CAROUSEL_SLOT_CATEGORY_TOP {
+		if req.SlotCategoryID == 0 {
+			return time.Time{}, time.Time{}, xerr.NewErrMsg("分类精选必须指定分类")
+		}
+		if _, err := dao.Category.GetCategoryByID(req.SlotCategoryID); err != nil {
+			return time.Time{}, time.Time{}, xerr.NewErrMsg("分类不存在")
+		}
+	}
+
+	// 3. 跳转目标
+	switch req.TargetType {
+	case constants.CAROUSEL_TARGET_PRODUCT:
+		if _, err := dao.Product.GetProductByID(req.ProductID); err != nil {
+			return time.Time{}, time.Time{}, xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+		}
+	case constants.CAROUSEL_TARGET_CATEGORY:
+		if _, err := dao.Category.GetCategoryByID(req.CategoryID); err != nil {
+			return time.Time{}, time.Time{}, xerr.NewErrMsg("跳转分类不存在")
+		}
+	case constants.CAROUSEL_TARGET_URL:
+		if req.URL == "" {
+			return time.Time{}, time.Time{}, xerr.NewErrMsg("跳转链接不能为空")
+		}
+	}
+
+	return startTime, endTime, nil
+}
+
+// toCarouselVO 运营位转 VO
+func toCarouselVO(carousel *model.Carousel) vo.CarouselVO {
+	return vo.CarouselVO{
+		ID:             carousel.ID,
+		Slot:           carousel.Slot,
+		SlotCategoryID: carousel.SlotCategoryID,
+		Title:          carousel.Title,
+		ImgPath:        carousel.ImgPath,
+		TargetType:     carousel.TargetType,
+		ProductID:      carousel.ProductID,
+		CategoryID:     carousel.CategoryID,
+		URL:            carousel.URL,
+		StartTime:      carousel.StartTime,
+		EndTime:        carousel.EndTime,
+		Sort:           carousel.Sort,
+		Status:         carousel.Status,
+	}
+}
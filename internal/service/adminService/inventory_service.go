@@ -0,0 +1,170 @@
+package adminService
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type InventoryService struct{}
+
+var Inventory = new(InventoryService)
+
+// 修改 SKU 下单预占方式（热点 SKU 可改为条件扣减，避免乐观锁冲突）
+func (s *InventoryService) UpdateSkuStockMode(req dto.UpdateSkuStockModeReq) error {
+	sku, err := dao.Product.GetSkuByID(req.ProductSKUID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+	if err := dao.Product.UpdateSkuStockMode(sku.ID, *req.StockMode); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	return nil
+}
+
+// SKU 库存流水（按时间倒序）
+func (s *InventoryService) StockMovementList(req dto.StockMovementListReq) (*vo.StockMovementListResp, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	// 1️⃣ 校验 SKU
+	sku, err := dao.Product.GetSkuByID(req.ProductSKUID)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+
+	// 2️⃣ 查询流水
+	movements, total, err := dao.Inventory.GetMovementList(sku.ID, req.Reason, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.InventoryMovementVO, 0, len(movements))
+	for _, movement := range movements {
+		list = append(list, vo.InventoryMovementVO{
+			ID:         movement.ID,
+			Delta:      movement.Delta,
+			Reason:     movement.Reason,
+			OrderNum:   movement.OrderNum,
+			Operator:   movement.Operator,
+			StockAfter: movement.StockAfter,
+			Remark:     movement.Remark,
+			CreatedAt:  movement.CreatedAt,
+		})
+	}
+	return &vo.StockMovementListResp{
+		ProductSkuID: sku.ID,
+		Stock:        sku.Stock,
+		Reserved:     sku.Reserved,
+		List:         list,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+	}, nil
+}
+
+// 设置 SKU 低库存告警阈值（立即按新阈值检查一次）
+func (s *InventoryService) UpdateSkuLowStockThreshold(req dto.UpdateSkuLowStockThresholdReq) error {
+	sku, err := dao.Product.GetSkuByID(req.ProductSKUID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+	if err := dao.Product.UpdateSkuLowStockThreshold(sku.ID, *req.Threshold); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	stockalert.CheckNow(sku.ID)
+	return nil
+}
+
+// 可售库存低于等于阈值的 SKU
+func (s *InventoryService) LowStockList(req dto.LowStockListReq) (*vo.LowStockListResp, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	// 1️⃣ 查询低库存 SKU
+	skus, total, err := dao.StockAlert.GetLowStockSkus(page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 2️⃣ 补充商品名称
+	productIDs := make([]uint, 0, len(skus))
+	for _, sku := range skus {
+		productIDs = append(productIDs, sku.ProductID)
+	}
+	products, err := dao.Product.GetProductsByIDs(productIDs)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.LowStockSkuVO, 0, len(skus))
+	for _, sku := range skus {
+		item := vo.LowStockSkuVO{
+			ProductID:    sku.ProductID,
+			ProductSkuID: sku.ID,
+			SkuTitle:     sku.Title,
+			Stock:        sku.Stock,
+			Reserved:     sku.Reserved,
+			Available:    sku.Stock - sku.Reserved,
+			Threshold:    sku.LowStockThreshold,
+		}
+		if product, ok := products[sku.ProductID]; ok {
+			item.ProductName = product.Name
+		}
+		list = append(list, item)
+	}
+	return &vo.LowStockListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// 低库存告警记录
+func (s *InventoryService) StockAlertList(req dto.StockAlertListReq) (*vo.StockAlertListResp, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	alerts, total, err := dao.StockAlert.GetAlertList(req.Status, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.StockAlertVO, 0, len(alerts))
+	for _, alert := range alerts {
+		list = append(list, vo.StockAlertVO{
+			ID:           alert.ID,
+			ProductID:    alert.ProductID,
+			ProductSkuID: alert.ProductSkuID,
+			Available:    alert.Available,
+			Threshold:    alert.Threshold,
+			Status:       alert.Status,
+			ResolvedAt:   alert.ResolvedAt,
+			CreatedAt:    alert.CreatedAt,
+		})
+	}
+	return &vo.StockAlertListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package adminService
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/pkg/metrics"
+)
+
+type MetricsService struct{}
+
+var Metrics = new(MetricsService)
+
+// 业务计数器（当前实例）
+func (s *MetricsService) GetMetrics(req dto.MetricsReq) *vo.MetricsResp {
+	samples := metrics.Snapshot(req.Prefix)
+	list := make([]vo.MetricVO, 0, len(samples))
+	for _, sample := range samples {
+		list = append(list, vo.MetricVO{
+			Name:   sample.Name,
+			Help:   sample.Help,
+			Value:  sample.Value,
+			Labels: sample.Labels,
+		})
+	}
+	return &vo.MetricsResp{List: list}
+}
@@ -0,0 +1,174 @@
+package adminService
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/orderevent"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+type OrderService struct{}
+
+var Order = new(OrderService)
+
+// 订单退款（模拟退款，只允许已支付未发货的订单）
+func (s *OrderService) RefundOrder(req dto.RefundOrderReq) error {
+	orderNo := req.OrderNo
+
+	// 1️⃣ 查询订单
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		return xerr.NewErrMsg("订单不存在")
+	}
+
+	// 2️⃣ 状态校验
+	if order.PayStatus != 1 || order.OrderStatus != 1 {
+		return xerr.NewErrMsg("只有已支付未发货的订单可以退款")
+	}
+
+	// 3️⃣ 事务：更新订单状态 + 回滚库存 + 发布退款事件（销量在同一事务内扣减）
+	var items []*model.OrderItem
+	var event *orderevent.Event
+	var seckillOrder model.SeckillOrder
+	var releasedSkuID uint
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		rowsAffected, err := dao.Order.RefundOrder(tx, orderNo, order.Version)
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return xerr.NewErrMsg("订单状态已变更，请刷新后重试")
+		}
+
+		if req.AdminRemark != "" {
+			if err := tx.Model(&model.Order{}).Where("order_num = ?", orderNo).
+				Update("admin_remark", req.AdminRemark).Error; err != nil {
+				return err
+			}
+		}
+
+		items, err = dao.Order.GetOrderItems(tx, orderNo)
+		if err != nil {
+			return err
+		}
+
+		if order.Type == 2 {
+			// 秒杀订单：活动在售时回补 Redis 秒杀库存，已删除时归还到 SKU
+			if err := tx.Where("order_num = ?", orderNo).First(&seckillOrder).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&seckillOrder).Update("status", 2).Error; err != nil { // 2=已取消
+				return err
+			}
+		} else {
+			for _, item := range items {
+				if err := dao.Product.IncrementStock(tx, item.ProductSkuID, item.Num,
+					constants.STOCK_REASON_REFUND, orderNo, req.Operator); err != nil {
+					return err
+				}
+			}
+		}
+
+		event = orderevent.New(orderevent.Refunded, order, items)
+		if err := orderevent.PublishTx(tx, event); err != nil {
+			return err
+		}
+
+		// 秒杀订单的库存归还放在事务最后一步（活动在售时会回补 Redis 秒杀库存）
+		if order.Type == 2 {
+			releasedSkuID, err = dao.Seckill.ReturnSeckillOrderStock(ctx, tx, seckillOrder.SeckillProductID, orderNo, req.Operator)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 4️⃣ 同步 Redis 库存
+	if order.Type == 2 {
+		dao.Rdb.Del(ctx, fmt.Sprintf("seckill:user:%d:%d", seckillOrder.SeckillProductID, order.UserID))
+		if releasedSkuID > 0 {
+			stockalert.Apply(ctx, map[uint]int{releasedSkuID: 1})
+		}
+	} else {
+		deltas := make(map[uint]int, len(items))
+		for _, item := range items {
+			deltas[item.ProductSkuID] += item.Num
+		}
+		stockalert.Apply(ctx, deltas)
+	}
+
+	// 5️⃣ 发布退款事件（异步处理器）
+	orderevent.Publish(event)
+	return nil
+}
+
+// 订单发货（只允许已支付未发货的订单，到期未确认收货的由延迟队列自动确认）
+func (s *OrderService) ShipOrder(req dto.ShipOrderReq) (*vo.ShipOrderResp, error) {
+	orderNo := req.OrderNo
+
+	// 1️⃣ 查询订单
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		return nil, xerr.NewErrMsg("订单不存在")
+	}
+
+	// 2️⃣ 状态校验
+	if order.PayStatus != 1 || order.OrderStatus != 1 {
+		return nil, xerr.NewErrMsg("只有已支付未发货的订单可以发货")
+	}
+
+	// 3️⃣ 事务：更新订单状态 + 发布发货事件
+	shipTime := time.Now()
+	autoConfirmTime := shipTime.AddDate(0, 0, config.AppConfig.Order.AutoConfirmDays)
+	var event *orderevent.Event
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		rowsAffected, err := dao.Order.ShipOrder(tx, orderNo, req.TrackingNumber, autoConfirmTime, order.Version)
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return xerr.NewErrMsg("订单状态已变更，请刷新后重试")
+		}
+
+		if req.AdminRemark != "" {
+			if err := tx.Model(&model.Order{}).Where("order_num = ?", orderNo).
+				Update("admin_remark", req.AdminRemark).Error; err != nil {
+				return err
+			}
+		}
+
+		items, err := dao.Order.GetOrderItems(tx, orderNo)
+		if err != nil {
+			return err
+		}
+		event = orderevent.New(orderevent.Shipped, order, items)
+		return orderevent.PublishTx(tx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	orderevent.Publish(event)
+
+	// 4️⃣ 加入自动确认收货队列（写入失败时由补偿任务按 MySQL 中的时间处理）
+	if err := dao.AutoConfirmQueue.Add(ctx, orderNo, autoConfirmTime); err != nil {
+		log.Printf("❌ 订单加入自动确认队列失败：%s, 错误：%v", orderNo, err)
+	}
+
+	return &vo.ShipOrderResp{
+		OrderNo:         orderNo,
+		ShipTime:        shipTime,
+		AutoConfirmTime: autoConfirmTime,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package adminService
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/constants"
+)
+
+// effectivePrice 商品实际售价（设置了折扣价时取折扣价）
+func effectivePrice(price, discountPrice int64) int64 {
+	if discountPrice > 0 && discountPrice < price {
+		return discountPrice
+	}
+	return price
+}
+
+// notifyPriceDrop 给收藏了该商品的所有用户写入降价通知
+func notifyPriceDrop(productID, skuID uint, oldPrice, newPrice int64) {
+	// 1️⃣ 查询收藏用户
+	userIDs, err := dao.Favorite.GetFavoriteUserIDs(productID)
+	if err != nil {
+		log.Printf("❌ 降价通知：查询收藏用户失败: product=%d, 错误: %v", productID, err)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	// 2️⃣ 组装通知内容
+	product, err := dao.Product.GetProductByID(productID)
+	if err != nil {
+		log.Printf("❌ 降价通知：查询商品失败: product=%d, 错误: %v", productID, err)
+		return
+	}
+	name := product.Name
+	if skuID > 0 {
+		if sku, err := dao.Product.GetSkuByID(skuID); err == nil {
+			name = product.Name + " - " + sku.Title
+		}
+	}
+
+	payload, _ := json.Marshal(types.PriceDropPayload{
+		ProductID:   productID,
+		SkuID:       skuID,
+		ProductName: name,
+		OldPrice:    oldPrice,
+		NewPrice:    newPrice,
+	})
+	content := fmt.Sprintf("您收藏的「%s」从 ¥%.2f 降至 ¥%.2f", name, float64(oldPrice)/100, float64(newPrice)/100)
+
+	// 3️⃣ 批量写入通知
+	notifications := make([]*model.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, &model.Notification{
+			UserID:  userID,
+			Type:    constants.NOTIFICATION_PRICE_DROP,
+			Title:   "收藏商品降价啦",
+			Content: content,
+			Payload: string(payload),
+		})
+	}
+	if err := dao.Notification.BatchCreate(notifications); err != nil {
+		log.Printf("❌ 降价通知：写入通知失败: product=%d, 错误: %v", productID, err)
+		return
+	}
+	log.Printf("✅ 降价通知：product=%d sku=%d 通知 %d 个用户", productID, skuID, len(notifications))
+}
@@ -0,0 +1,318 @@
+package adminService
+
+import (
+	"errors"
+	"log"
+	"time"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/pricing"
+	"xiaomi-mall/pkg/constants"
+	"xiaomi-mall/pkg/idgen"
+	parseTime "xiaomi-mall/pkg/parsetime"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+type PriceRuleService struct{}
+
+var PriceRule = new(PriceRuleService)
+
+// 创建价格规则（开始时间已到的规则立即生效）
+func (s *PriceRuleService) CreatePriceRule(req dto.CreatePriceRuleReq) (*vo.PriceRuleVO, error) {
+	// 1️⃣ 校验时间
+	startTime, err := parseTime.ParseDateTimeStr(req.StartTime)
+	if err != nil {
+		return nil, xerr.NewErrMsg("开始时间格式错误")
+	}
+	endTime, err := parseTime.ParseDateTimeStr(req.EndTime)
+	if err != nil {
+		return nil, xerr.NewErrMsg("结束时间格式错误")
+	}
+	if !endTime.After(startTime) {
+		return nil, xerr.NewErrMsg("结束时间必须晚于开始时间")
+	}
+	if !endTime.After(time.Now()) {
+		return nil, xerr.NewErrMsg("结束时间必须晚于当前时间")
+	}
+
+	// 2️⃣ 校验 SKU 和时间冲突
+	sku, err := dao.Product.GetSkuByID(req.ProductSkuID)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+	overlapped, err := dao.Price.HasOverlappingRule(sku.ID, startTime, endTime)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if overlapped {
+		return nil, xerr.NewErrMsg("该 SKU 在此时间段内已有价格规则")
+	}
+
+	// 3️⃣ 创建规则
+	rule := &model.PriceRule{
+		ProductID:    sku.ProductID,
+		ProductSkuID: sku.ID,
+		Price:        req.Price,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Status:       constants.PRICE_RULE_STATUS_ACTIVE,
+		Remark:       req.Remark,
+	}
+	// 补齐规则生效前的售价记录，保证之后能算出降价幅度
+	refreshSkuPrices([]uint{sku.ID}, constants.PRICE_SOURCE_BASE)
+	if err := dao.Price.CreateRule(rule); err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 4️⃣ 已经开始的规则立即生效（未开始的由定时任务在开始时处理）
+	if !startTime.After(time.Now()) {
+		refreshSkuPrices([]uint{sku.ID}, constants.PRICE_SOURCE_RULE)
+	}
+
+	resp := toPriceRuleVO(rule)
+	return &resp, nil
+}
+
+// 取消价格规则（生效中的规则取消后立即恢复原价）
+func (s *PriceRuleService) CancelPriceRule(req dto.PriceRuleIDReq) error {
+	rule, err := dao.Price.GetRuleByID(req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return xerr.NewErrMsg("价格规则不存在")
+		}
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	affected, err := dao.Price.CancelRule(rule.ID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if affected == 0 {
+		return xerr.NewErrMsg("价格规则已取消")
+	}
+
+	now := time.Now()
+	if !rule.StartTime.After(now) && rule.EndTime.After(now) {
+		refreshSkuPrices([]uint{rule.ProductSkuID}, constants.PRICE_SOURCE_RULE)
+	}
+	return nil
+}
+
+// 价格规则列表
+func (s *PriceRuleService) PriceRuleList(req dto.PriceRuleListReq) (*vo.PriceRuleListResp, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	rules, total, err := dao.Price.GetRuleList(req.ProductSkuID, req.Status, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.PriceRuleVO, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, toPriceRuleVO(rule))
+	}
+	return &vo.PriceRuleListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// 售价历史（可查询指定时刻顾客看到的价格）
+func (s *PriceRuleService) PriceHistory(req dto.PriceHistoryReq) (*vo.PriceHistoryResp, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	resp := &vo.PriceHistoryResp{ProductSkuID: req.ProductSkuID, Page: page, PageSize: pageSize}
+
+	// 1️⃣ 指定时刻的售价
+	if req.At != "" {
+		at, err := parseTime.ParseDateTimeStr(req.At)
+		if err != nil {
+			return nil, xerr.NewErrMsg("时间格式错误")
+		}
+		history, err := dao.Price.GetHistoryAt(req.ProductSkuID, at)
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+		if history != nil {
+			priceAt := toPriceHistoryVO(history)
+			resp.PriceAt = &priceAt
+		}
+	}
+
+	// 2️⃣ 变更记录
+	histories, total, err := dao.Price.GetHistoryList(req.ProductSkuID, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	resp.List = make([]vo.PriceHistoryVO, 0, len(histories))
+	for _, history := range histories {
+		resp.List = append(resp.List, toPriceHistoryVO(history))
+	}
+	resp.Total = total
+	return resp, nil
+}
+
+// 排期任务锁的过期时间（持有者异常退出时锁自动释放）
+const priceRuleLockTTL = time.Minute
+
+// RunScheduledPriceChanges 处理水位到 now 之间开始或结束的价格规则（由定时任务调用）
+// 多实例时只有拿到锁的实例执行；水位保存在 Redis，停机期间跨过的边界在下次执行时补齐
+func (s *PriceRuleService) RunScheduledPriceChanges(now time.Time) {
+	// 1️⃣ 获取排期锁（其他实例正在处理时跳过本轮）
+	token := idgen.GenStringID()
+	ok, err := dao.Price.TryLockRuleSchedule(ctx, token, priceRuleLockTTL)
+	if err != nil {
+		log.Printf("❌ 价格规则：获取排期锁失败: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer dao.Price.UnlockRuleSchedule(ctx, token)
+
+	// 2️⃣ 读取水位（首次运行从当前时刻开始）
+	from, ok, err := dao.Price.GetRuleWatermark(ctx)
+	if err != nil {
+		log.Printf("❌ 价格规则：读取水位失败: %v", err)
+		return
+	}
+	if ok {
+		if !now.After(from) {
+			return
+		}
+		// 处理失败时不推进水位，下一轮重试（已记录的 SKU 会因售价相同被跳过）
+		if err := s.ApplyScheduledPriceChanges(from, now); err != nil {
+			log.Printf("❌ 价格规则：处理 %s ~ %s 的规则失败: %v", from.Format(time.DateTime), now.Format(time.DateTime), err)
+			return
+		}
+	}
+
+	// 3️⃣ 推进水位
+	if err := dao.Price.SetRuleWatermark(ctx, now); err != nil {
+		log.Printf("❌ 价格规则：更新水位失败: %v", err)
+	}
+}
+
+// ApplyScheduledPriceChanges 处理 (from, to] 内开始或结束的价格规则
+// 按边界时刻依次记录售价历史，生效时间取规则的开始/结束时刻而不是处理时刻
+func (s *PriceRuleService) ApplyScheduledPriceChanges(from, to time.Time) error {
+	boundaries, err := dao.Price.GetRuleBoundariesBetween(from, to)
+	if err != nil {
+		return err
+	}
+
+	// 边界已按时间升序，同一时刻的 SKU 一起处理
+	updated := 0
+	for i := 0; i < len(boundaries); {
+		at := boundaries[i].At
+		var skuIDs []uint
+		for ; i < len(boundaries) && boundaries[i].At.Equal(at); i++ {
+			skuIDs = append(skuIDs, boundaries[i].SkuID)
+		}
+		if err := refreshSkuPricesAt(skuIDs, constants.PRICE_SOURCE_RULE, at); err != nil {
+			return err
+		}
+		updated += len(skuIDs)
+	}
+	if updated > 0 {
+		log.Printf("✅ 价格规则：%d 个 SKU 售价已更新", updated)
+	}
+	return nil
+}
+
+// refreshSkuPrices 实际售价可能变化后的统一处理：失效详情缓存、追加售价历史、降价通知
+func refreshSkuPrices(skuIDs []uint, source string) {
+	if err := refreshSkuPricesAt(skuIDs, source, time.Now()); err != nil {
+		log.Printf("❌ 刷新售价失败: %v, 错误: %v", skuIDs, err)
+	}
+}
+
+// refreshSkuPricesAt 同 refreshSkuPrices，售价历史以 at 作为生效时间
+func refreshSkuPricesAt(skuIDs []uint, source string, at time.Time) error {
+	if len(skuIDs) == 0 {
+		return nil
+	}
+
+	// 1️⃣ 失效商品详情和 SKU 详情缓存（缓存中的价格是加载时计算的）
+	skus, err := dao.Product.GetSkusByIDs(skuIDs)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(skus)*2)
+	for _, sku := range skus {
+		keys = append(keys, cache.ProductDetailKey(sku.ProductID), cache.SkuDetailKey(sku.ID))
+	}
+	cache.Invalidate(ctx, keys...)
+
+	// 2️⃣ 追加售价历史
+	changes, err := pricing.RecordChangesAt(skuIDs, source, at)
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ 降价通知收藏用户（补处理的边界可能已经过去，只通知当前仍是这个价格的 SKU）
+	var drops []pricing.Change
+	for _, change := range changes {
+		if change.NewPrice < change.OldPrice {
+			drops = append(drops, change)
+		}
+	}
+	if len(drops) == 0 {
+		return nil
+	}
+	current, err := pricing.ResolveSkus(skus, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, change := range drops {
+		if current[change.SkuID].Price == change.NewPrice {
+			go notifyPriceDrop(change.ProductID, change.SkuID, change.OldPrice, change.NewPrice)
+		}
+	}
+	return nil
+}
+
+// toPriceRuleVO 价格规则转 VO
+func toPriceRuleVO(rule *model.PriceRule) vo.PriceRuleVO {
+	return vo.PriceRuleVO{
+		ID:           rule.ID,
+		ProductID:    rule.ProductID,
+		ProductSkuID: rule.ProductSkuID,
+		Price:        rule.Price,
+		StartTime:    rule.StartTime,
+		EndTime:      rule.EndTime,
+		Status:       rule.Status,
+		Remark:       rule.Remark,
+		CreatedAt:    rule.CreatedAt,
+	}
+}
+
+// toPriceHistoryVO 售价记录转 VO
+func toPriceHistoryVO(history *model.PriceHistory) vo.PriceHistoryVO {
+	return vo.PriceHistoryVO{
+		Price:       history.Price,
+		BasePrice:   history.BasePrice,
+		RuleID:      history.RuleID,
+		Source:      history.Source,
+		EffectiveAt: history.EffectiveAt,
+	}
+}
@@ -0,0 +1,102 @@
+package adminService
+
+import (
+	"io"
+	"strconv"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/sheet"
+)
+
+const exportBatchSize = 500 // 每批导出的商品数
+
+// ExportProducts 按筛选条件导出商品目录（一行一个 SKU，列与导入模板一致，导出的文件修改后可直接导入）
+// 数据分批查询、边查边写，调用方需在调用前设置好响应头
+func (s *ImportService) ExportProducts(req dto.ExportProductsReq, w io.Writer) error {
+	// 1️⃣ 创建表格并写表头
+	format := req.Format
+	if format == "" {
+		format = sheet.FormatXLSX
+	}
+	sw, err := sheet.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	header := make([]string, 0, len(productSheetColumns))
+	for _, col := range productSheetColumns {
+		header = append(header, col.label)
+	}
+	if err := sw.Write(header); err != nil {
+		return err
+	}
+
+	// 2️⃣ 分类名称
+	categories, err := dao.Category.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	// 3️⃣ 按商品 ID 分批写出
+	var afterID uint
+	for {
+		products, err := dao.Product.ScanProducts(req.CategoryID, req.Keyword, req.OnSale, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			break
+		}
+		productIDs := make([]uint, 0, len(products))
+		for _, product := range products {
+			productIDs = append(productIDs, product.ID)
+		}
+		skus, err := dao.Product.GetSkusByProductIDs(productIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			onSale := "0"
+			if product.OnSale {
+				onSale = "1"
+			}
+			discount := ""
+			if product.DiscountPrice > 0 {
+				discount = formatYuan(product.DiscountPrice)
+			}
+			category := categoryNames[product.CategoryID]
+			if category == "" {
+				category = strconv.FormatUint(uint64(product.CategoryID), 10)
+			}
+
+			for _, sku := range skus[product.ID] {
+				row := []string{
+					strconv.FormatUint(uint64(product.ID), 10),
+					product.SpuCode,
+					product.Name,
+					category,
+					product.Title,
+					product.Info,
+					product.ImgPath,
+					formatYuan(product.Price),
+					discount,
+					onSale,
+					sku.Code,
+					sku.Title,
+					formatYuan(sku.Price),
+					strconv.Itoa(sku.Stock),
+				}
+				if err := sw.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	return sw.Close()
+}
@@ -0,0 +1,763 @@
+package adminService
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/bloom"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/sheet"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+type ImportService struct{}
+
+var Import = new(ImportService)
+
+const (
+	MaxImportFileSize    = 20 << 20 // 导入文件大小上限（20MB）
+	maxImportRows        = 50000    // 单次导入最大数据行数
+	importProgressPeriod = 200      // 每处理多少行更新一次进度
+
+	importLockTTL  = 30 * time.Second // 导入锁有效期（执行期间定时续期）
+	importLockWait = 2 * time.Second  // 等待其他导入任务结束的轮询间隔
+)
+
+// productSheetColumns 商品导入导出的列（一行一个 SKU，同一商品的多行按商品ID / SPU编码归并）
+// 导入时表头可以写 key 或中文名，导出使用中文名
+var productSheetColumns = []struct{ key, label string }{
+	{"product_id", "商品ID"}, // 已有商品的 ID（导出时填写，修改后重新导入时按此更新）
+	{"spu_code", "SPU编码"},  // 商家 SPU 编码，新商品按此归并
+	{"name", "商品名称"},       // 必填
+	{"category", "分类"},     // 必填，分类名称或分类 ID
+	{"title", "标题"},
+	{"info", "描述"},
+	{"img_path", "封面图"},       // 为空时不修改
+	{"price", "展示价"},          // 单位：元，为空时取 SKU 最低价
+	{"discount_price", "折扣价"}, // 单位：元，为空表示不打折
+	{"on_sale", "上架"},         // 1/0、是/否，为空时不修改（新商品默认不上架）
+	{"sku_code", "SKU编码"},     // 必填，按此 upsert
+	{"sku_title", "规格"},       // 必填
+	{"sku_price", "SKU价格"},    // 必填，单位：元
+	{"stock", "库存"},           // 为空时不修改（新 SKU 为 0）
+}
+
+var requiredProductColumns = []string{"name", "category", "sku_code", "sku_title", "sku_price"}
+
+// importRow 解析后的一行数据
+type importRow struct {
+	line  int      // 文件中的行号（从 1 开始，表头为第 1 行）
+	cells []string // 原始单元格（写入错误报告）
+
+	productID     uint
+	spuCode       string
+	name          string
+	categoryID    uint
+	title         string
+	info          string
+	imgPath       string
+	price         int64 // 0 表示未填写
+	discountPrice int64
+	onSale        *bool
+	skuCode       string
+	skuTitle      string
+	skuPrice      int64
+	stock         *int
+}
+
+// importGroup 同一个商品（SPU）的所有行，SPU 字段以第一行为准
+type importGroup struct {
+	key  string
+	rows []*importRow
+}
+
+// importRowError 导入失败的行
+type importRowError struct {
+	row     *importRow
+	message string
+}
+
+// importGroupResult 一个商品导入成功后需要在事务外处理的后续动作
+type importGroupResult struct {
+	productID uint
+	created   bool         // 新建的商品（需要加入布隆过滤器）
+	skuIDs    []uint       // 涉及的 SKU（清理缓存）
+	stocks    map[uint]int // 库存有变化的 SKU（同步库存镜像）
+	oldPrice  int64        // 商品原实际售价（0 表示新商品）
+	newPrice  int64
+	failed    []importRowError // 组内单独失败的行（如 SKU 编码已属于其他商品）
+}
+
+// CreateProductImport 创建商品导入任务并在后台执行
+func (s *ImportService) CreateProductImport(fileName string, data []byte) (*vo.CreateImportJobResp, error) {
+	// 1️⃣ 校验文件格式
+	format := sheet.FormatOf(fileName)
+	if format == "" {
+		return nil, xerr.NewErrMsg("只支持 CSV、XLSX 文件")
+	}
+
+	// 2️⃣ 创建任务
+	job := &model.ImportJob{
+		Type:     constants.IMPORT_TYPE_PRODUCT,
+		FileName: fileName,
+		Status:   constants.IMPORT_STATUS_PENDING,
+	}
+	if err := dao.ImportJob.CreateJob(job); err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 3️⃣ 后台执行（接口立即返回任务 ID，前端轮询进度）
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("❌ 商品导入任务 panic: job=%d, %v", job.ID, r)
+				dao.ImportJob.Finish(job.ID, constants.IMPORT_STATUS_FAILED, "导入任务异常中断", "")
+			}
+		}()
+		s.runProductImport(job.ID, data, format)
+	}()
+
+	return &vo.CreateImportJobResp{JobID: job.ID}, nil
+}
+
+// lockImport 等待并获取导入锁，执行期间定时续期，返回释放函数
+func lockImport(jobID uint) (func(), error) {
+	token := fmt.Sprintf("job:%d", jobID)
+	for {
+		ok, err := dao.ImportJob.TryLock(ctx, token, importLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+		time.Sleep(importLockWait)
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(importLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if ok, err := dao.ImportJob.RenewLock(ctx, token, importLockTTL); err == nil && !ok {
+					log.Printf("⚠️  商品导入：job=%d 的导入锁已失效", jobID)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		dao.ImportJob.Unlock(ctx, token)
+	}, nil
+}
+
+// 查询导入任务进度
+func (s *ImportService) GetImportJob(req dto.ImportJobIDReq) (*vo.ImportJobVO, error) {
+	job, err := dao.ImportJob.GetJob(req.JobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, xerr.NewErrMsg("导入任务不存在")
+		}
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	return &vo.ImportJobVO{
+		JobID:          job.ID,
+		Type:           job.Type,
+		FileName:       job.FileName,
+		Status:         job.Status,
+		TotalRows:      job.TotalRows,
+		ProcessedRows:  job.ProcessedRows,
+		SuccessRows:    job.SuccessRows,
+		FailedRows:     job.FailedRows,
+		Message:        job.Message,
+		HasErrorReport: job.ErrorReport != "",
+		CreatedAt:      job.CreatedAt,
+		FinishedAt:     job.FinishedAt,
+	}, nil
+}
+
+// 下载导入错误报告（CSV：行号 + 原始数据 + 错误原因）
+func (s *ImportService) GetImportErrorReport(req dto.ImportJobIDReq) (string, error) {
+	job, err := dao.ImportJob.GetJob(req.JobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", xerr.NewErrMsg("导入任务不存在")
+		}
+		return "", xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if job.ErrorReport == "" {
+		return "", xerr.NewErrMsg("该任务没有错误报告")
+	}
+	return job.ErrorReport, nil
+}
+
+// FailInterruptedImports 服务启动时把上次未执行完的导入任务标记为失败
+func (s *ImportService) FailInterruptedImports() {
+	n, err := dao.ImportJob.FailUnfinished("服务重启，导入任务中断，请重新上传")
+	if err != nil {
+		log.Printf("⚠️  标记中断的导入任务失败: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("⚠️  %d 个导入任务因服务重启中断", n)
+	}
+}
+
+// runProductImport 执行商品导入：解析 → 逐行校验 → 按商品分组 upsert → 生成错误报告
+func (s *ImportService) runProductImport(jobID uint, data []byte, format string) {
+	// 同一时间只执行一个导入任务（按编码 upsert，并发执行会重复创建同一编码的商品），排队期间任务保持待处理
+	unlock, err := lockImport(jobID)
+	if err != nil {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, "获取导入锁失败: "+err.Error(), "")
+		return
+	}
+	defer unlock()
+
+	// 1️⃣ 解析文件
+	rows, err := sheet.ReadAll(data, format, maxImportRows+1)
+	if errors.Is(err, sheet.ErrTooManyRows) {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, fmt.Sprintf("单次最多导入 %d 行", maxImportRows), "")
+		return
+	}
+	if err != nil {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, "文件解析失败: "+err.Error(), "")
+		return
+	}
+	if len(rows) < 2 {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, "文件中没有数据行", "")
+		return
+	}
+	header := rows[0]
+	columns, err := mapImportColumns(header)
+	if err != nil {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, err.Error(), "")
+		return
+	}
+
+	total := 0
+	for _, cells := range rows[1:] {
+		if !isBlankCells(cells) {
+			total++
+		}
+	}
+	dao.ImportJob.Start(jobID, total)
+	log.Printf("📥 商品导入开始: job=%d, 共 %d 行", jobID, total)
+
+	// 2️⃣ 逐行校验并按商品分组
+	categories, err := loadCategoryIndex()
+	if err != nil {
+		dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_FAILED, "查询分类失败", "")
+		return
+	}
+	var failed []importRowError
+	var groups []*importGroup
+	groupIndex := make(map[string]*importGroup)
+	seenCodes := make(map[string]int) // SKU 编码 → 首次出现的行号
+
+	for i, cells := range rows[1:] {
+		line := i + 2
+		if isBlankCells(cells) {
+			continue
+		}
+		row, err := parseImportRow(line, cells, columns, categories)
+		if err != nil {
+			failed = append(failed, importRowError{row: row, message: err.Error()})
+			continue
+		}
+		if first, ok := seenCodes[row.skuCode]; ok {
+			failed = append(failed, importRowError{row: row, message: fmt.Sprintf("SKU编码与第 %d 行重复", first)})
+			continue
+		}
+		seenCodes[row.skuCode] = line
+
+		key := "spu:" + row.spuCode
+		if row.productID > 0 {
+			key = fmt.Sprintf("id:%d", row.productID)
+		}
+		group, ok := groupIndex[key]
+		if !ok {
+			group = &importGroup{key: key}
+			groupIndex[key] = group
+			groups = append(groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+
+	// 3️⃣ 逐个商品导入（每个商品一个事务，互不影响）
+	processed, success := len(failed), 0
+	reported := 0
+	for _, group := range groups {
+		result, err := s.importProductGroup(jobID, group)
+		if err != nil {
+			for _, row := range group.rows {
+				failed = append(failed, importRowError{row: row, message: err.Error()})
+			}
+		} else {
+			failed = append(failed, result.failed...)
+			success += len(group.rows) - len(result.failed)
+			if result.productID > 0 {
+				afterProductImported(result)
+			}
+		}
+
+		processed += len(group.rows)
+		if processed-reported >= importProgressPeriod {
+			dao.ImportJob.UpdateProgress(jobID, processed, success, len(failed))
+			reported = processed
+		}
+	}
+	dao.ImportJob.UpdateProgress(jobID, processed, success, len(failed))
+
+	// 4️⃣ 生成错误报告并结束任务
+	report := ""
+	if len(failed) > 0 {
+		report = buildImportErrorReport(header, failed)
+	}
+	dao.ImportJob.Finish(jobID, constants.IMPORT_STATUS_DONE, "", report)
+	log.Printf("✅ 商品导入完成: job=%d, 成功 %d 行, 失败 %d 行", jobID, success, len(failed))
+}
+
+// importProductGroup 导入一个商品及其 SKU（单个事务）
+// 返回 error 表示整组失败；组内个别行的失败记录在 result.failed 中
+func (s *ImportService) importProductGroup(jobID uint, group *importGroup) (*importGroupResult, error) {
+	first := group.rows[0]
+
+	// 1️⃣ SPU 级校验（价格为空时取 SKU 最低价）
+	price := first.price
+	if price == 0 {
+		for _, row := range group.rows {
+			if price == 0 || row.skuPrice < price {
+				price = row.skuPrice
+			}
+		}
+	}
+	if first.discountPrice > price {
+		return nil, errors.New("折扣价不能高于展示价")
+	}
+
+	// 补齐已有 SKU 改价前的售价记录，保证导入后能算出降价幅度
+	codes := make([]string, 0, len(group.rows))
+	for _, row := range group.rows {
+		codes = append(codes, row.skuCode)
+	}
+	if before, err := dao.Product.GetSkusByCodes(dao.DB, codes); err == nil && len(before) > 0 {
+		skuIDs := make([]uint, 0, len(before))
+		for _, sku := range before {
+			skuIDs = append(skuIDs, sku.ID)
+		}
+		refreshSkuPrices(skuIDs, constants.PRICE_SOURCE_BASE)
+	}
+
+	result := &importGroupResult{stocks: make(map[uint]int)}
+	err := dao.DB.Transaction(func(tx *gorm.DB) error {
+		// 2️⃣ 查询已存在的 SKU
+		existing, err := dao.Product.GetSkusByCodes(tx, codes)
+		if err != nil {
+			return err
+		}
+
+		// 3️⃣ 确定目标商品：商品ID > SPU编码 > 已存在 SKU 所属的商品
+		product, err := resolveImportProduct(tx, first, existing, group.rows)
+		if err != nil {
+			return err
+		}
+
+		// 4️⃣ 新建或更新商品
+		if product == nil {
+			product = &model.Product{OnSale: false}
+			result.created = true
+		} else {
+			result.oldPrice = effectivePrice(product.Price, product.DiscountPrice)
+		}
+		if first.spuCode != "" {
+			product.SpuCode = first.spuCode
+		}
+		product.Name = first.name
+		product.CategoryID = first.categoryID
+		product.Title = first.title
+		product.Info = first.info
+		if first.imgPath != "" {
+			product.ImgPath = first.imgPath
+		}
+		product.Price = price
+		product.DiscountPrice = first.discountPrice
+		if first.onSale != nil {
+			product.OnSale = *first.onSale
+		}
+		if result.created {
+			if err := dao.Product.CreateProductSPU(tx, product); err != nil {
+				return err
+			}
+		} else if err := dao.Product.SaveProduct(tx, product); err != nil {
+			return err
+		}
+		result.productID = product.ID
+		result.newPrice = effectivePrice(product.Price, product.DiscountPrice)
+
+		// 5️⃣ 按 SKU 编码 upsert
+		var newSkus []*model.ProductSku
+		for _, row := range group.rows {
+			sku, ok := existing[row.skuCode]
+			if ok && sku.ProductID != product.ID {
+				result.failed = append(result.failed, importRowError{
+					row:     row,
+					message: fmt.Sprintf("SKU编码已属于其他商品（商品ID %d）", sku.ProductID),
+				})
+				continue
+			}
+
+			if !ok {
+				sku = &model.ProductSku{ProductID: product.ID, Code: row.skuCode, Title: row.skuTitle, Price: row.skuPrice}
+				if row.stock != nil {
+					sku.Stock = *row.stock
+				}
+				newSkus = append(newSkus, sku)
+				continue
+			}
+
+			if row.stock != nil && *row.stock < sku.Reserved {
+				result.failed = append(result.failed, importRowError{
+					row:     row,
+					message: fmt.Sprintf("库存不能小于未支付订单预占的数量 %d", sku.Reserved),
+				})
+				continue
+			}
+			oldStock := sku.Stock
+			sku.Title = row.skuTitle
+			sku.Price = row.skuPrice
+			if row.stock != nil {
+				sku.Stock = *row.stock
+			}
+			if err := dao.Product.SaveSku(tx, sku, oldStock, fmt.Sprintf("导入任务 %d", jobID)); err != nil {
+				return err
+			}
+			result.skuIDs = append(result.skuIDs, sku.ID)
+			if sku.Stock != oldStock {
+				result.stocks[sku.ID] = sku.Stock
+			}
+		}
+		if len(newSkus) > 0 {
+			if err := dao.Product.CreateProductSKUs(tx, newSkus); err != nil {
+				return err
+			}
+			for _, sku := range newSkus {
+				result.skuIDs = append(result.skuIDs, sku.ID)
+				result.stocks[sku.ID] = sku.Stock
+			}
+		}
+
+		// 整组的 SKU 都失败时不保留新建的空商品
+		if len(result.failed) == len(group.rows) {
+			return errImportGroupEmpty
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errImportGroupEmpty) {
+			return &importGroupResult{failed: result.failed}, nil // 事务已回滚，失败原因已逐行记录
+		}
+		var importErr *importError
+		if errors.As(err, &importErr) {
+			return nil, err
+		}
+		log.Printf("❌ 商品导入：保存失败: %s, 错误: %v", group.key, err)
+		return nil, errors.New("保存失败，请稍后重试")
+	}
+	return result, nil
+}
+
+// importError 可以直接展示给用户的导入错误
+type importError struct{ msg string }
+
+func (e *importError) Error() string { return e.msg }
+
+var errImportGroupEmpty = errors.New("import: no sku imported")
+
+// resolveImportProduct 确定导入行对应的已有商品（返回 nil 表示需要新建）
+func resolveImportProduct(tx *gorm.DB, first *importRow, existing map[string]*model.ProductSku, rows []*importRow) (*model.Product, error) {
+	// 1. 指定了商品 ID
+	if first.productID > 0 {
+		var products []*model.Product
+		if err := tx.Model(&model.Product{}).Where("id = ?", first.productID).Limit(1).Find(&products).Error; err != nil {
+			return nil, err
+		}
+		if len(products) == 0 {
+			return nil, &importError{msg: fmt.Sprintf("商品ID %d 不存在", first.productID)}
+		}
+		return products[0], nil
+	}
+
+	// 2. 按 SPU 编码
+	if first.spuCode != "" {
+		product, err := dao.Product.GetProductBySpuCode(tx, first.spuCode)
+		if err != nil || product != nil {
+			return product, err
+		}
+	}
+
+	// 3. 按已存在 SKU 所属的商品（SPU 编码是新加的，SKU 是以前手工创建的）
+	for _, row := range rows {
+		if sku, ok := existing[row.skuCode]; ok {
+			var products []*model.Product
+			if err := tx.Model(&model.Product{}).Where("id = ?", sku.ProductID).Limit(1).Find(&products).Error; err != nil {
+				return nil, err
+			}
+			if len(products) > 0 {
+				return products[0], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// afterProductImported 商品导入事务提交后的后续处理：布隆过滤器、库存镜像、缓存、售价历史、降价通知
+func afterProductImported(result *importGroupResult) {
+	if result.created {
+		bloom.AddProductToBloom(result.productID)
+	}
+	if len(result.stocks) > 0 {
+		// 镜像为可售库存（需扣除预占），按导入后的数据库值回写
+		skuIDs := make([]uint, 0, len(result.stocks))
+		for skuID := range result.stocks {
+			skuIDs = append(skuIDs, skuID)
+		}
+		if stocks, err := dao.Product.GetSkuStocksByIDs(skuIDs); err == nil {
+			for skuID, stock := range stocks {
+				dao.Stock.SetSkuStock(ctx, skuID, stock)
+			}
+		}
+		stockalert.Check(skuIDs...)
+	}
+
+	cache.Invalidate(ctx, cache.ProductDetailKey(result.productID))
+	refreshSkuPrices(result.skuIDs, constants.PRICE_SOURCE_BASE) // 同时失效 SKU 详情缓存
+
+	if !result.created && result.newPrice < result.oldPrice {
+		go notifyPriceDrop(result.productID, 0, result.oldPrice, result.newPrice)
+	}
+}
+
+// mapImportColumns 解析表头，返回 key → 列号
+func mapImportColumns(header []string) (map[string]int, error) {
+	aliases := make(map[string]string, len(productSheetColumns)*2)
+	for _, col := range productSheetColumns {
+		aliases[col.key] = col.key
+		aliases[col.label] = col.key
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		if key, ok := aliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[key] = i
+		}
+	}
+
+	var missing []string
+	for _, key := range requiredProductColumns {
+		if _, ok := columns[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	_, hasID := columns["product_id"]
+	_, hasSpu := columns["spu_code"]
+	if !hasID && !hasSpu {
+		missing = append(missing, "product_id 或 spu_code")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("表头缺少列: %s", strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+// categoryIndex 分类校验用的索引（按 ID 和名称查找）
+type categoryIndex struct {
+	byID   map[uint]bool
+	byName map[string]uint
+}
+
+func loadCategoryIndex() (*categoryIndex, error) {
+	categories, err := dao.Category.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	index := &categoryIndex{byID: make(map[uint]bool), byName: make(map[string]uint)}
+	for _, category := range categories {
+		index.byID[category.ID] = true
+		index.byName[category.Name] = category.ID
+	}
+	return index, nil
+}
+
+// parseImportRow 解析并校验一行数据（出错时仍返回 row，用于写错误报告）
+func parseImportRow(line int, cells []string, columns map[string]int, categories *categoryIndex) (*importRow, error) {
+	row := &importRow{line: line, cells: cells}
+	get := func(key string) string {
+		if i, ok := columns[key]; ok && i < len(cells) {
+			return cells[i]
+		}
+		return ""
+	}
+
+	// 1. 商品归并键
+	if v := get("product_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || id == 0 {
+			return row, errors.New("商品ID无效")
+		}
+		row.productID = uint(id)
+	}
+	row.spuCode = get("spu_code")
+	if row.productID == 0 && row.spuCode == "" {
+		return row, errors.New("商品ID和SPU编码至少填写一个")
+	}
+	if len(row.spuCode) > 64 {
+		return row, errors.New("SPU编码不能超过 64 个字符")
+	}
+
+	// 2. 商品字段
+	row.name = get("name")
+	if row.name == "" {
+		return row, errors.New("商品名称不能为空")
+	}
+	if len([]rune(row.name)) > 255 {
+		return row, errors.New("商品名称不能超过 255 个字符")
+	}
+	category := get("category")
+	if id, err := strconv.ParseUint(category, 10, 64); err == nil && categories.byID[uint(id)] {
+		row.categoryID = uint(id)
+	} else if id, ok := categories.byName[category]; ok {
+		row.categoryID = id
+	} else {
+		return row, fmt.Errorf("分类 %q 不存在", category)
+	}
+	row.title = get("title")
+	row.info = get("info")
+	if len([]rune(row.info)) > 1000 {
+		return row, errors.New("描述不能超过 1000 个字符")
+	}
+	row.imgPath = get("img_path")
+	if row.imgPath != "" && !isValidImageURL(row.imgPath) {
+		return row, errors.New("封面图地址无效")
+	}
+
+	var err error
+	if row.price, err = parseYuan(get("price"), false); err != nil {
+		return row, fmt.Errorf("展示价%s", err.Error())
+	}
+	if row.discountPrice, err = parseYuan(get("discount_price"), false); err != nil {
+		return row, fmt.Errorf("折扣价%s", err.Error())
+	}
+	if v := get("on_sale"); v != "" {
+		onSale, ok := parseImportBool(v)
+		if !ok {
+			return row, errors.New("上架只能填写 1/0 或 是/否")
+		}
+		row.onSale = &onSale
+	}
+
+	// 3. SKU 字段
+	row.skuCode = get("sku_code")
+	if row.skuCode == "" {
+		return row, errors.New("SKU编码不能为空")
+	}
+	if len(row.skuCode) > 64 {
+		return row, errors.New("SKU编码不能超过 64 个字符")
+	}
+	row.skuTitle = get("sku_title")
+	if row.skuTitle == "" {
+		return row, errors.New("规格不能为空")
+	}
+	if row.skuPrice, err = parseYuan(get("sku_price"), true); err != nil {
+		return row, fmt.Errorf("SKU价格%s", err.Error())
+	}
+	if v := get("stock"); v != "" {
+		stock, err := strconv.Atoi(v)
+		if err != nil || stock < 0 {
+			return row, errors.New("库存必须是大于等于 0 的整数")
+		}
+		row.stock = &stock
+	}
+	return row, nil
+}
+
+// parseYuan 解析以元为单位的金额（最多两位小数），返回分
+func parseYuan(s string, required bool) (int64, error) {
+	if s == "" {
+		if required {
+			return 0, errors.New("不能为空")
+		}
+		return 0, nil
+	}
+	yuan, fen, hasFen := strings.Cut(s, ".")
+	if len(fen) > 2 || (hasFen && fen == "") || yuan == "" {
+		return 0, errors.New("格式错误（单位：元，最多两位小数）")
+	}
+	for len(fen) < 2 {
+		fen += "0"
+	}
+	v, err := strconv.ParseInt(yuan+fen, 10, 64)
+	if err != nil || v < 0 || strings.HasPrefix(yuan, "-") || strings.HasPrefix(yuan, "+") {
+		return 0, errors.New("格式错误（单位：元，最多两位小数）")
+	}
+	if required && v == 0 {
+		return 0, errors.New("必须大于 0")
+	}
+	return v, nil
+}
+
+// formatYuan 分转换为元（保留两位小数）
+func formatYuan(fen int64) string {
+	return fmt.Sprintf("%d.%02d", fen/100, fen%100)
+}
+
+func parseImportBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "1", "true", "是", "y", "yes":
+		return true, true
+	case "0", "false", "否", "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+func isBlankCells(cells []string) bool {
+	for _, cell := range cells {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// buildImportErrorReport 生成错误报告：行号 + 原始数据 + 错误原因，按行号排序
+func buildImportErrorReport(header []string, failed []importRowError) string {
+	sort.Slice(failed, func(i, j int) bool { return failed[i].row.line < failed[j].row.line })
+
+	var buf bytes.Buffer
+	w, _ := sheet.NewWriter(&buf, sheet.FormatCSV)
+	w.Write(append(append([]string{"行号"}, header...), "错误原因"))
+	for _, f := range failed {
+		cells := make([]string, len(header))
+		copy(cells, f.row.cells)
+		w.Write(append(append([]string{strconv.Itoa(f.row.line)}, cells...), f.message))
+	}
+	w.Close()
+	return buf.String()
+}
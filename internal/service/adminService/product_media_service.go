@@ -0,0 +1,116 @@
+package adminService
+
+import (
+	"encoding/json"
+	"strings"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+// 替换商品 / SKU 图集（第一张图同步为封面）
+func (s *ProductService) UpdateProductImages(req dto.UpdateProductImagesReq) error {
+	// 1️⃣ 校验商品和 SKU
+	if _, err := dao.Product.GetProductByID(req.ProductID); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+	}
+	invalidKeys := []string{cache.ProductDetailKey(req.ProductID)}
+	if req.ProductSkuID > 0 {
+		sku, err := dao.Product.GetSkuByID(req.ProductSkuID)
+		if err != nil {
+			return xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+		}
+		if sku.ProductID != req.ProductID {
+			return xerr.NewErrCode(xerr.PRODUCT_SKU_MISMATCH)
+		}
+		invalidKeys = append(invalidKeys, cache.SkuDetailKey(sku.ID))
+	}
+
+	// 2️⃣ 校验图片地址
+	for _, url := range req.Images {
+		if !isValidImageURL(url) {
+			return xerr.NewErrMsg("图片地址无效")
+		}
+	}
+
+	// 3️⃣ 替换图集，第一张同步为封面（清空图集时保留原封面）
+	err := dao.DB.Transaction(func(tx *gorm.DB) error {
+		if err := dao.Product.ReplaceImages(tx, req.ProductID, req.ProductSkuID, req.Images); err != nil {
+			return err
+		}
+		if len(req.Images) == 0 {
+			return nil
+		}
+		return dao.Product.UpdateCover(tx, req.ProductID, req.ProductSkuID, req.Images[0])
+	})
+	if err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
+	}
+
+	cache.Invalidate(ctx, invalidKeys...)
+	return nil
+}
+
+// 保存商品图文详情
+func (s *ProductService) UpdateProductContent(req dto.UpdateProductContentReq) error {
+	if _, err := dao.Product.GetProductByID(req.ProductID); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+	}
+
+	blocks, err := encodeContentBlocks(req.Content)
+	if err != nil {
+		return err
+	}
+	if err := dao.Product.SaveContent(dao.DB, req.ProductID, blocks); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
+	}
+
+	cache.Invalidate(ctx, cache.ProductDetailKey(req.ProductID))
+	return nil
+}
+
+// encodeContentBlocks 校验并规整图文详情块，返回存库用的 JSON
+// 只保留每种块类型需要的字段，文本不解析 HTML，由客户端按纯文本渲染
+func encodeContentBlocks(blocks []types.ContentBlock) (string, error) {
+	cleaned := make([]types.ContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Type {
+		case types.ContentBlockText, types.ContentBlockHeading:
+			text := strings.TrimSpace(block.Text)
+			if text == "" {
+				continue // 空段落直接丢弃
+			}
+			cleaned = append(cleaned, types.ContentBlock{Type: block.Type, Text: text})
+		case types.ContentBlockImage:
+			if !isValidImageURL(block.URL) {
+				return "", xerr.NewErrMsg("图文详情中的图片地址无效")
+			}
+			cleaned = append(cleaned, types.ContentBlock{
+				Type:   block.Type,
+				URL:    block.URL,
+				Width:  block.Width,
+				Height: block.Height,
+			})
+		default:
+			return "", xerr.NewErrMsg("不支持的内容块类型")
+		}
+	}
+
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return "", xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	return string(data), nil
+}
+
+// isValidImageURL 图片只允许 http(s) 绝对地址或站内路径（如本地存储的 /uploads/...）
+func isValidImageURL(url string) bool {
+	if strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") {
+		return len(url) > len("https://")
+	}
+	return strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "//")
+}
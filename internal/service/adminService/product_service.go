@@ -2,11 +2,17 @@ package adminService
 
 import (
 	"context"
+	"log"
+	"time"
 	"xiaomi-mall/internal/api/dto"
 	"xiaomi-mall/internal/api/vo"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
 	"xiaomi-mall/internal/pkg/bloom"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
+	parseTime "xiaomi-mall/pkg/parsetime"
 	"xiaomi-mall/pkg/xerr"
 
 	"gorm.io/gorm"
@@ -20,6 +26,24 @@ var ctx = context.Background()
 
 // CreateProduct 添加商品（SPU + SKU）- 使用事务
 func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProductResp, error) {
+	// 0️⃣ 校验图集和图文详情
+	for _, url := range req.Images {
+		if !isValidImageURL(url) {
+			return nil, xerr.NewErrMsg("图片地址无效")
+		}
+	}
+	for _, skuReq := range req.SKUs {
+		for _, url := range skuReq.Images {
+			if !isValidImageURL(url) {
+				return nil, xerr.NewErrMsg("图片地址无效")
+			}
+		}
+	}
+	content, err := encodeContentBlocks(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1️⃣ 准备 SPU 数据
 	product := &model.Product{
 		Name:          req.Name,
@@ -33,9 +57,13 @@ func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProd
 		Num:           0,
 		ClickNum:      0,
 	}
+	if product.ImgPath == "" && len(req.Images) > 0 {
+		product.ImgPath = req.Images[0] // 未指定封面时使用图集第一张
+	}
 
 	// 2️⃣ 开启事务
-	err := dao.DB.Transaction(func(tx *gorm.DB) error {
+	var createdSkus []*model.ProductSku
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
 		// 2.1 创建 SPU
 		if err := dao.Product.CreateProductSPU(tx, product); err != nil {
 			return err // 返回错误会自动回滚
@@ -52,6 +80,9 @@ func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProd
 				Code:      skuReq.Code,
 				Version:   0, // 初始版本号
 			}
+			if len(skuReq.Images) > 0 {
+				sku.ImgPath = skuReq.Images[0]
+			}
 			skus = append(skus, sku)
 		}
 
@@ -60,12 +91,22 @@ func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProd
 			return err
 		}
 
-		// 2.4 如果需要初始化 Redis 库存，也在这里做
-		// for _, sku := range skus {
-		//     redisKey := fmt.Sprintf("sku:stock:%d", sku.ID)
-		//     dao.Redis.Set(redisKey, sku.Stock, 0)
-		// }
+		// 2.4 图集和图文详情（封面已在准备数据时确定）
+		if err := dao.Product.ReplaceImages(tx, product.ID, 0, req.Images); err != nil {
+			return err
+		}
+		for i, sku := range skus {
+			if err := dao.Product.ReplaceImages(tx, product.ID, sku.ID, req.SKUs[i].Images); err != nil {
+				return err
+			}
+		}
+		if len(req.Content) > 0 {
+			if err := dao.Product.SaveContent(tx, product.ID, content); err != nil {
+				return err
+			}
+		}
 
+		createdSkus = skus
 		return nil // 返回 nil 表示成功，自动提交事务
 	})
 
@@ -77,6 +118,19 @@ func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProd
 	// 3.5 添加到布隆过滤器
 	bloom.AddProductToBloom(product.ID)
 
+	// 3.6 初始化 Redis 库存镜像
+	for _, sku := range createdSkus {
+		dao.Stock.SetSkuStock(ctx, sku.ID, sku.Stock)
+	}
+
+	// 3.7 清理可能存在的空值缓存，记录初始售价
+	cache.Invalidate(ctx, cache.ProductDetailKey(product.ID))
+	skuIDs := make([]uint, 0, len(createdSkus))
+	for _, sku := range createdSkus {
+		skuIDs = append(skuIDs, sku.ID)
+	}
+	refreshSkuPrices(skuIDs, constants.PRICE_SOURCE_BASE)
+
 	// 4️⃣ 构造响应 VO
 	resp := &vo.CreateProductResp{
 		ProductID:     product.ID,
@@ -92,11 +146,79 @@ func (s *ProductService) CreateProduct(req dto.CreateProductReq) (*vo.CreateProd
 	return resp, nil
 }
 
-// 更新商品库存
-func (s *ProductService) UpdateProductStock(req dto.UpdateProductStockReq) error {
-	err := dao.Product.UpdateSkuStock(req.ProductSKUID, req.Stock)
+// 调整商品库存（增减量 + 原因，与库存流水在同一事务内写入）
+func (s *ProductService) UpdateProductStock(req dto.UpdateProductStockReq) (*vo.UpdateProductStockResp, error) {
+	sku, err := dao.Product.GetSkuByID(req.ProductSKUID)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+
+	var movement *model.InventoryMovement
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		movement, err = dao.Inventory.ChangeStock(tx, dao.StockChange{
+			SkuID:    sku.ID,
+			Delta:    req.Delta,
+			Reason:   constants.STOCK_REASON_ADMIN_ADJUST,
+			Operator: req.Operator,
+			Remark:   req.Reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if movement == nil {
+		return nil, xerr.NewErrMsg("可售库存不足，无法扣减（未支付订单预占的库存不能扣减）")
+	}
+
+	// 同步库存镜像，检查低库存 / 到货提醒
+	stockalert.Apply(ctx, map[uint]int{sku.ID: req.Delta})
+
+	// 删除商品详情和 SKU 详情缓存（广播到所有实例）
+	cache.Invalidate(ctx, cache.ProductDetailKey(sku.ProductID), cache.SkuDetailKey(sku.ID))
+	return &vo.UpdateProductStockResp{ProductSKUID: sku.ID, Stock: movement.StockAfter}, nil
+}
+
+// 更新 SKU 原价（实际售价降低时通知收藏用户）
+func (s *ProductService) UpdateSkuPrice(req dto.UpdateSkuPriceReq) error {
+	sku, err := dao.Product.GetSkuByID(req.ProductSKUID)
 	if err != nil {
-		return xerr.NewErrCode(xerr.PRODUCT_CREATE_ERROR)
+		return xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+
+	// 补齐改价前的售价记录（历史数据可能没有），保证能算出降价幅度
+	refreshSkuPrices([]uint{sku.ID}, constants.PRICE_SOURCE_BASE)
+
+	if err := dao.Product.UpdateSkuPrice(sku.ID, req.Price); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
+	}
+
+	// 失效缓存、记录售价历史、降价通知（有生效中的价格规则时实际售价不变，不会通知）
+	refreshSkuPrices([]uint{sku.ID}, constants.PRICE_SOURCE_BASE)
+	return nil
+}
+
+// 更新商品展示价格 / 折扣价（实际售价降低时通知收藏用户）
+func (s *ProductService) UpdateProductPrice(req dto.UpdateProductPriceReq) error {
+	product, err := dao.Product.GetProductByID(req.ProductID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+	}
+	if req.DiscountPrice > req.Price {
+		return xerr.NewErrMsg("折扣价不能高于原价")
+	}
+
+	if err := dao.Product.UpdateProductPrice(req.ProductID, req.Price, req.DiscountPrice); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
+	}
+
+	cache.Invalidate(ctx, cache.ProductDetailKey(req.ProductID))
+
+	// 降价检测：比较实际售价（有折扣价时取折扣价）
+	oldPrice := effectivePrice(product.Price, product.DiscountPrice)
+	newPrice := effectivePrice(req.Price, req.DiscountPrice)
+	if newPrice < oldPrice {
+		go notifyPriceDrop(req.ProductID, 0, oldPrice, newPrice)
 	}
 	return nil
 }
@@ -107,5 +229,112 @@ func (s *ProductService) UpdateProductOnSale(req dto.UpdateProductOnSaleReq) err
 	if err != nil {
 		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
 	}
+
+	invalidateProductCaches([]uint{req.ProductID})
+	return nil
+}
+
+// 设置定时上下架
+func (s *ProductService) UpdateSaleSchedule(req dto.UpdateSaleScheduleReq) error {
+	// 1️⃣ 解析时间（为空表示取消）
+	now := time.Now()
+	var onSaleAt, offSaleAt *time.Time
+	if req.OnSaleAt != "" {
+		t, err := parseTime.ParseDateTimeStr(req.OnSaleAt)
+		if err != nil {
+			return xerr.NewErrMsg("上架时间格式错误")
+		}
+		if !t.After(now) {
+			return xerr.NewErrMsg("上架时间必须晚于当前时间")
+		}
+		onSaleAt = &t
+	}
+	if req.OffSaleAt != "" {
+		t, err := parseTime.ParseDateTimeStr(req.OffSaleAt)
+		if err != nil {
+			return xerr.NewErrMsg("下架时间格式错误")
+		}
+		if !t.After(now) {
+			return xerr.NewErrMsg("下架时间必须晚于当前时间")
+		}
+		offSaleAt = &t
+	}
+	if onSaleAt != nil && offSaleAt != nil && !offSaleAt.After(*onSaleAt) {
+		return xerr.NewErrMsg("下架时间必须晚于上架时间")
+	}
+
+	// 2️⃣ 保存
+	if _, err := dao.Product.GetProductByID(req.ProductID); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+	}
+	if err := dao.Product.UpdateSaleSchedule(req.ProductID, onSaleAt, offSaleAt); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_UPDATE_ERROR)
+	}
 	return nil
 }
+
+// ApplySaleSchedules 执行到期的定时上下架并失效缓存（由定时任务调用）
+func (s *ProductService) ApplySaleSchedules(now time.Time) {
+	listed, delisted, err := dao.Product.ApplySaleSchedules(now)
+	if err != nil {
+		log.Printf("❌ 定时上下架失败: %v", err)
+		return
+	}
+	if len(listed)+len(delisted) == 0 {
+		return
+	}
+	invalidateProductCaches(append(listed, delisted...))
+	cache.Invalidate(ctx, cache.HomeKey) // 首页热销 / 运营位可能包含这些商品
+	log.Printf("✅ 定时上下架：上架 %d 个，下架 %d 个", len(listed), len(delisted))
+}
+
+// invalidateProductCaches 失效商品详情及其所有 SKU 详情缓存（上下架状态会影响 SKU 详情）
+func invalidateProductCaches(productIDs []uint) {
+	keys := make([]string, 0, len(productIDs))
+	for _, productID := range productIDs {
+		keys = append(keys, cache.ProductDetailKey(productID))
+		skus, err := dao.Product.GetSkusByProductID(productID)
+		if err != nil {
+			log.Printf("⚠️  查询商品 SKU 失败: product=%d, 错误: %v", productID, err)
+			continue
+		}
+		for _, sku := range skus {
+			keys = append(keys, cache.SkuDetailKey(sku.ID))
+		}
+	}
+	cache.Invalidate(ctx, keys...)
+}
+
+// 商品浏览统计（每日浏览量 + 独立访客）
+func (s *ProductService) ProductViewStats(req dto.ProductViewStatsReq) (*vo.ProductViewStatsResp, error) {
+	days := req.Days
+	if days <= 0 {
+		days = 7
+	}
+
+	// 最近 N 天的日期（升序）
+	dates := make([]string, 0, days)
+	now := time.Now()
+	for i := days - 1; i >= 0; i-- {
+		dates = append(dates, now.AddDate(0, 0, -i).Format("20060102"))
+	}
+
+	views, uv, err := dao.View.GetDailyStats(ctx, req.ProductID, dates)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	list := make([]vo.DailyViewVO, 0, len(dates))
+	for _, date := range dates {
+		list = append(list, vo.DailyViewVO{
+			Date:  date,
+			Views: views[date],
+			UV:    uv[date],
+		})
+	}
+
+	return &vo.ProductViewStatsResp{
+		ProductID: req.ProductID,
+		List:      list,
+	}, nil
+}
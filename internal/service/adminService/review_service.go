@@ -0,0 +1,23 @@
+package adminService
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type ReviewService struct{}
+
+var Review = new(ReviewService)
+
+// 商家回复评价（重复回复会覆盖上一次回复）
+func (s *ReviewService) ReplyReview(req dto.ReplyReviewReq) error {
+	rowsAffected, err := dao.Review.Reply(req.ReviewID, req.Reply)
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if rowsAffected == 0 {
+		return xerr.NewErrMsg("评价不存在")
+	}
+	return nil
+}
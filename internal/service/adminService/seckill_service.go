@@ -9,8 +9,13 @@ import (
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
 	"xiaomi-mall/internal/pkg/bloom"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
 	parseTime "xiaomi-mall/pkg/parsetime"
 	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
 )
 
 type SeckillService struct{}
@@ -30,6 +35,9 @@ func (s *SeckillService) CreateSeckillProduct(req dto.CreateSeckillProductReq) (
 	if err != nil {
 		return nil, xerr.NewErrMsg("商品库存不存在")
 	}
+	if sku.ProductID != product.ID {
+		return nil, xerr.NewErrMsg("SKU 不属于该商品")
+	}
 	if sku.Stock < int(req.SeckillStock) {
 		return nil, xerr.NewErrMsg("商品库存不足")
 	}
@@ -50,22 +58,116 @@ func (s *SeckillService) CreateSeckillProduct(req dto.CreateSeckillProductReq) (
 		Status:       0,
 		Version:      0,
 	}
-	err = dao.Seckill.CreateSeckillProduct(seckill)
+	// 5. 事务：创建秒杀商品 + 从 SKU 库存中预留秒杀库存（记录库存流水）
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		if err := dao.Seckill.CreateSeckillProduct(tx, seckill); err != nil {
+			return err
+		}
+		movement, err := dao.Inventory.ChangeStock(tx, dao.StockChange{
+			SkuID:    sku.ID,
+			Delta:    -int(req.SeckillStock),
+			Reason:   constants.STOCK_REASON_SECKILL_RESERVE,
+			OrderNum: dao.SeckillStockRef(seckill.ID),
+		})
+		if err != nil {
+			return err
+		}
+		if movement == nil {
+			return xerr.NewErrMsg("商品库存不足")
+		}
+		return nil
+	})
 	if err != nil {
+		if codeErr, ok := err.(*xerr.CodeError); ok {
+			return nil, codeErr
+		}
 		return nil, xerr.NewErrMsg("创建秒杀商品失败")
 	}
+	stockalert.Apply(ctx, map[uint]int{sku.ID: -int(req.SeckillStock)})
 
 	// 添加到布隆过滤器
 	bloom.AddSeckillToBloom(seckill.ID)
 
+	// 秒杀商品是热点商品：详情缓存切换为逻辑过期模式（删除旧缓存，下次回源时生效）
+	dao.Product.MarkHotProduct(ctx, product.ID)
+	cache.Invalidate(ctx, cache.ProductDetailKey(product.ID))
+
 	return &vo.CreateSeckillProductResp{
 		ID: seckill.ID,
 	}, nil
 }
 
 // 删除秒杀商品
+// 创建活动时会从 SKU 库存中预留秒杀库存（seckill_reserve 流水），删除时只归还 Redis 中尚未售出的部分；
+// 预留机制上线前创建的活动没有预留流水，删除时不归还
+// 删除时仍占用库存的订单（待支付、已支付、队列中尚未落库的）之后关闭或退款时，由 ReturnSeckillOrderStock 逐件归还到 SKU
 func (s *SeckillService) DeleteSeckillProduct(req dto.DeleteSeckillProductReq) error {
-	return dao.Seckill.DeleteSeckillProduct(req.ID)
+	seckillProduct, err := dao.Seckill.GetSeckillProductByID(req.ID)
+	if err != nil {
+		return xerr.NewErrMsg("秒杀商品不存在")
+	}
+
+	// 1️⃣ 先停止售卖：原子地移出活动列表并取走 Redis 剩余库存，之后不会再产生新的秒杀订单
+	// （事务失败时活动保持停售，重试删除时沿用第一次停售时的剩余库存；停售后关闭的订单由关单流程直接归还到 SKU）
+	remaining, preheated, err := dao.Seckill.StopSeckillSale(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	// 2️⃣ 事务：删除秒杀商品 + 归还未售出的预留库存（记录库存流水）
+	var released int
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		rowsAffected, err := dao.Seckill.DeleteSeckillProduct(tx, req.ID)
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return nil // 已被并发删除，预留库存已归还
+		}
+		reserved, err := dao.Inventory.HasMovement(tx, dao.SeckillStockRef(seckillProduct.ID), constants.STOCK_REASON_SECKILL_RESERVE)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			return nil // 预留机制上线前创建的活动，没有从 SKU 扣过库存
+		}
+		if preheated {
+			// 已售出（含待支付）的部分由订单占用，只归还 Redis 中剩余的库存
+			released = remaining
+		} else {
+			// 未预热或库存 key 已过期：Redis 中没有剩余可参考，按订单占用数计算
+			holding, err := dao.Seckill.CountHoldingSeckillOrders(tx, req.ID)
+			if err != nil {
+				return err
+			}
+			released = int(seckillProduct.TotalStock) - int(holding)
+		}
+		if released > int(seckillProduct.TotalStock) {
+			released = int(seckillProduct.TotalStock)
+		}
+		if released <= 0 {
+			released = 0
+			return nil
+		}
+		_, err = dao.Inventory.ChangeStock(tx, dao.StockChange{
+			SkuID:    seckillProduct.SkuID,
+			Delta:    released,
+			Reason:   constants.STOCK_REASON_SECKILL_RELEASE,
+			OrderNum: dao.SeckillStockRef(seckillProduct.ID),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ 同步 Redis 中的 SKU 库存
+	if released > 0 {
+		stockalert.Apply(ctx, map[uint]int{seckillProduct.SkuID: released})
+	}
+
+	cache.Invalidate(ctx, cache.ProductDetailKey(seckillProduct.ProductID), cache.SkuDetailKey(seckillProduct.SkuID))
+	return nil
 }
 
 // 手动开启/结束秒杀（同步更新 MySQL 和 Redis）
@@ -100,6 +202,9 @@ func (s *SeckillService) UpdateSeckillStatus(req dto.UpdateSeckillStatusReq) err
 		dao.Seckill.RemoveFromActiveList(ctx, req.ID)
 	}
 
+	// 4. 秒杀活动变更，关联商品的详情缓存失效
+	cache.Invalidate(ctx, cache.ProductDetailKey(seckillProduct.ProductID))
+
 	return nil
 }
 
@@ -127,9 +232,7 @@ func (s *SeckillService) PreheatSeckillProduct(req dto.PreheatSeckillProductReq)
 	if err != nil {
 		return xerr.NewErrMsg("商品sku不存在")
 	}
-	if sku.Stock < int(seckillProduct.SeckillStock) {
-		return xerr.NewErrMsg("商品库存不足")
-	}
+	// 秒杀库存已在创建活动时从 SKU 库存中预留，这里不再校验 SKU 库存
 
 	//2. 【Redis String】设置库存
 	//2. 设置库存（调用 DAO 方法）
@@ -160,7 +263,8 @@ func (s *SeckillService) PreheatSeckillProduct(req dto.PreheatSeckillProductReq)
 		return xerr.NewErrMsg("预热失败: " + err.Error())
 	}
 
-	//4. 【布隆过滤器】添加商品 ID（可选）
+	//4. 【缓存】关联商品的详情缓存失效
+	cache.Invalidate(ctx, cache.ProductDetailKey(product.ID))
 
 	//5. 【数据库】更新预热状态（可选）
 	return nil
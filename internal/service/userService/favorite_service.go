@@ -0,0 +1,106 @@
+package userService
+
+import (
+	"log"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type FavoriteService struct{}
+
+var Favorite = new(FavoriteService)
+
+// 添加收藏
+func (s *FavoriteService) AddFavorite(userID uint, req dto.FavoriteReq) error {
+	// 1️⃣ 校验商品是否存在
+	if _, err := dao.Product.GetProductByID(req.ProductID); err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+	}
+
+	// 2️⃣ 写入 MySQL（重复收藏幂等）
+	added, err := dao.Favorite.AddFavorite(userID, req.ProductID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 3️⃣ 同步 Redis 收藏集合
+	if added {
+		if err := dao.Favorite.SyncFavoriteCache(ctx, userID, req.ProductID, true); err != nil {
+			log.Printf("⚠️  同步收藏缓存失败: user=%d product=%d, 错误: %v", userID, req.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// 取消收藏
+func (s *FavoriteService) RemoveFavorite(userID uint, req dto.FavoriteReq) error {
+	removed, err := dao.Favorite.RemoveFavorite(userID, req.ProductID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	if removed {
+		if err := dao.Favorite.SyncFavoriteCache(ctx, userID, req.ProductID, false); err != nil {
+			log.Printf("⚠️  同步收藏缓存失败: user=%d product=%d, 错误: %v", userID, req.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// 收藏列表
+func (s *FavoriteService) FavoriteList(userID uint, req dto.FavoriteListReq) (*vo.FavoriteListResp, error) {
+	// 1️⃣ 分页参数默认值
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	// 2️⃣ 查询收藏记录
+	favorites, total, err := dao.Favorite.GetUserFavorites(userID, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 3️⃣ 批量查询商品信息
+	list := make([]vo.FavoriteItemVO, 0, len(favorites))
+	if len(favorites) > 0 {
+		productIDs := make([]uint, 0, len(favorites))
+		for _, favorite := range favorites {
+			productIDs = append(productIDs, favorite.ProductID)
+		}
+		products, err := dao.Product.GetProductsByIDs(productIDs)
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+
+		for _, favorite := range favorites {
+			product, ok := products[favorite.ProductID]
+			if !ok {
+				continue // 商品已删除
+			}
+			list = append(list, vo.FavoriteItemVO{
+				ProductID:     product.ID,
+				Name:          product.Name,
+				Title:         product.Title,
+				ImgPath:       product.ImgPath,
+				Price:         product.Price,
+				DiscountPrice: product.DiscountPrice,
+				OnSale:        product.OnSale,
+				FavoritedAt:   favorite.CreatedAt,
+			})
+		}
+	}
+
+	return &vo.FavoriteListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package userService
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type HistoryService struct{}
+
+var History = new(HistoryService)
+
+// 浏览历史列表（按最近浏览时间倒序）
+func (s *HistoryService) HistoryList(userID uint, req dto.HistoryListReq) (*vo.HistoryListResp, error) {
+	// 1️⃣ 分页参数默认值
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	// 2️⃣ 查询 Redis 浏览历史
+	entries, total, err := dao.History.GetHistory(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	// 3️⃣ 批量查询商品信息
+	list := make([]vo.HistoryItemVO, 0, len(entries))
+	if len(entries) > 0 {
+		productIDs := make([]uint, 0, len(entries))
+		for _, entry := range entries {
+			productIDs = append(productIDs, entry.ProductID)
+		}
+		products, err := dao.Product.GetProductsByIDs(productIDs)
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+
+		for _, entry := range entries {
+			product, ok := products[entry.ProductID]
+			if !ok {
+				continue // 商品已删除
+			}
+			list = append(list, vo.HistoryItemVO{
+				ProductID:     product.ID,
+				Name:          product.Name,
+				Title:         product.Title,
+				ImgPath:       product.ImgPath,
+				Price:         product.Price,
+				DiscountPrice: product.DiscountPrice,
+				OnSale:        product.OnSale,
+				ViewedAt:      entry.ViewedAt,
+			})
+		}
+	}
+
+	return &vo.HistoryListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// 删除单条浏览历史
+func (s *HistoryService) DeleteHistory(userID uint, req dto.DeleteHistoryReq) error {
+	if err := dao.History.RemoveHistory(ctx, userID, req.ProductID); err != nil {
+		return xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	return nil
+}
+
+// 清空浏览历史
+func (s *HistoryService) ClearHistory(userID uint) error {
+	if err := dao.History.ClearHistory(ctx, userID); err != nil {
+		return xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	return nil
+}
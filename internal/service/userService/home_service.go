@@ -0,0 +1,159 @@
+package userService
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/constants"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type HomeService struct{}
+
+var Home = new(HomeService)
+
+// 首页缓存配置：所有用户共用一份，热点 key 使用逻辑过期
+// 运营位变更、排期开始/结束时由后台任务主动失效
+var homeCacheOpts = cache.Options{
+	TTL:           5 * time.Minute,
+	Jitter:        30 * time.Second,
+	LogicalExpire: true,
+	Local:         true,
+}
+
+const (
+	homeSeckillSize    = 6  // 首页展示的秒杀活动数
+	homeTopSellersSize = 10 // 首页热销商品数
+)
+
+// 首页聚合数据（轮播 + 分类精选 + 新品 + 秒杀 + 热销）
+func (s *HomeService) HomeIndex() (*vo.HomeResp, error) {
+	resp, err := cache.Fetch(ctx, cache.HomeKey, homeCacheOpts, s.loadHome)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	return resp, nil
+}
+
+// loadHome 从数据库 / Redis 组装首页数据（缓存回源）
+func (s *HomeService) loadHome() (*vo.HomeResp, error) {
+	resp := &vo.HomeResp{
+		Carousel:      make([]vo.SlotVO, 0),
+		CategoryPicks: make([]vo.CategoryPickVO, 0),
+		NewArrivals:   make([]vo.SlotVO, 0),
+		Seckill:       make([]vo.HomeSeckillVO, 0),
+		TopSellers:    make([]vo.ProductItemVO, 0),
+	}
+
+	// ========== 1️⃣ 当前生效的运营位（已按 sort 排序） ==========
+	carousels, err := dao.Carousel.GetActiveCarousels(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	picks := make(map[uint]*vo.CategoryPickVO)
+	categoryOrder := make([]uint, 0)
+	for _, carousel := range carousels {
+		slot := toSlotVO(carousel)
+		switch carousel.Slot {
+		case constants.CAROUSEL_SLOT_HOME:
+			resp.Carousel = append(resp.Carousel, slot)
+		case constants.CAROUSEL_SLOT_NEW_ARRIVAL:
+			resp.NewArrivals = append(resp.NewArrivals, slot)
+		case constants.CAROUSEL_SLOT_CATEGORY_TOP:
+			pick, ok := picks[carousel.SlotCategoryID]
+			if !ok {
+				pick = &vo.CategoryPickVO{CategoryID: carousel.SlotCategoryID, Items: make([]vo.SlotVO, 0)}
+				picks[carousel.SlotCategoryID] = pick
+				categoryOrder = append(categoryOrder, carousel.SlotCategoryID)
+			}
+			pick.Items = append(pick.Items, slot)
+		}
+	}
+
+	// 分类精选补充分类名称（按第一个运营位的顺序排列）
+	for _, categoryID := range categoryOrder {
+		pick := picks[categoryID]
+		if category, err := dao.Category.GetCategoryByID(categoryID); err == nil {
+			pick.CategoryName = category.Name
+		}
+		resp.CategoryPicks = append(resp.CategoryPicks, *pick)
+	}
+
+	// ========== 2️⃣ 即将结束的秒杀活动（seckill:active:end 按结束时间升序） ==========
+	// 秒杀数据在 Redis，失败不影响首页其他模块
+	if seckill, err := loadHomeSeckill(); err != nil {
+		log.Printf("⚠️  首页：查询秒杀活动失败: %v", err)
+	} else {
+		resp.Seckill = seckill
+	}
+
+	// ========== 3️⃣ 热销商品（按销量倒序） ==========
+	onSale := true
+	products, _, err := dao.Product.GetProductList(0, "", &onSale, "num", "desc", 1, homeTopSellersSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range products {
+		resp.TopSellers = append(resp.TopSellers, toProductItemVO(product))
+	}
+
+	return resp, nil
+}
+
+// loadHomeSeckill 查询首页展示的秒杀活动
+func loadHomeSeckill() ([]vo.HomeSeckillVO, error) {
+	seckillIDs, _, err := dao.Seckill.GetActiveSeckillIDs(ctx, 1, homeSeckillSize)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]vo.HomeSeckillVO, 0, len(seckillIDs))
+	if len(seckillIDs) == 0 {
+		return list, nil
+	}
+
+	productDataMap, err := dao.Seckill.BatchGetSeckillProductCache(ctx, seckillIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range seckillIDs {
+		data, ok := productDataMap[id]
+		if !ok {
+			continue
+		}
+		var product types.SeckillProductCache
+		if err := json.Unmarshal(data, &product); err != nil {
+			continue
+		}
+		list = append(list, vo.HomeSeckillVO{
+			ID:            product.SeckillID,
+			ProductID:     product.ProductID,
+			ProductName:   product.ProductName,
+			ImgPath:       product.ProductImg,
+			OriginalPrice: product.OriginalPrice,
+			SeckillPrice:  product.SeckillPrice,
+			StartTime:     time.Unix(product.StartTime, 0),
+			EndTime:       time.Unix(product.EndTime, 0),
+		})
+	}
+	return list, nil
+}
+
+// toSlotVO 运营位转首页 VO
+func toSlotVO(carousel *model.Carousel) vo.SlotVO {
+	return vo.SlotVO{
+		ID:         carousel.ID,
+		Title:      carousel.Title,
+		ImgPath:    carousel.ImgPath,
+		TargetType: carousel.TargetType,
+		ProductID:  carousel.ProductID,
+		CategoryID: carousel.CategoryID,
+		URL:        carousel.URL,
+	}
+}
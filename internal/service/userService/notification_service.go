@@ -0,0 +1,61 @@
+package userService
+
+import (
+	"encoding/json"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type NotificationService struct{}
+
+var Notification = new(NotificationService)
+
+// 通知列表
+func (s *NotificationService) NotificationList(userID uint, req dto.NotificationListReq) (*vo.NotificationListResp, error) {
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	notifications, total, err := dao.Notification.GetUserNotifications(userID, req.UnreadOnly, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.NotificationVO, 0, len(notifications))
+	for _, n := range notifications {
+		item := vo.NotificationVO{
+			ID:        n.ID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Content:   n.Content,
+			IsRead:    n.IsRead,
+			CreatedAt: n.CreatedAt,
+		}
+		if n.Payload != "" {
+			item.Payload = json.RawMessage(n.Payload)
+		}
+		list = append(list, item)
+	}
+
+	return &vo.NotificationListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// 标记通知已读
+func (s *NotificationService) ReadNotifications(userID uint, req dto.ReadNotificationReq) error {
+	if err := dao.Notification.MarkRead(userID, req.IDs); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package userService
+
+import (
+	"errors"
+	"log"
+	"time"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/constants"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+const autoConfirmBatch = 100
+
+// 延长收货（每个订单只能延长一次，自动确认时间顺延）
+func (s *OrderService) ExtendAutoConfirm(userID uint, req dto.ExtendConfirmReq) (*vo.ExtendConfirmResp, error) {
+	orderNo := req.OrderNo
+
+	// 1️⃣ 查询订单 + 权限校验
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		return nil, xerr.NewErrMsg("订单不存在")
+	}
+	if order.UserID != userID {
+		return nil, xerr.NewErrMsg("订单不属于当前用户")
+	}
+
+	// 2️⃣ 状态校验
+	if order.OrderStatus != constants.ORDER_STATUS_SHIPPED || order.AutoConfirmTime == nil {
+		return nil, xerr.NewErrMsg("只有已发货的订单可以延长收货")
+	}
+	if order.ConfirmExtended {
+		return nil, xerr.NewErrMsg("每个订单只能延长一次收货")
+	}
+
+	// 3️⃣ 顺延自动确认时间（乐观锁）
+	extendDays := config.AppConfig.Order.ConfirmExtendDays
+	autoConfirmTime := order.AutoConfirmTime.AddDate(0, 0, extendDays)
+	rowsAffected, err := dao.Order.ExtendAutoConfirm(dao.DB, orderNo, autoConfirmTime, order.Version)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if rowsAffected == 0 {
+		return nil, xerr.NewErrMsg("订单状态已变更，请刷新后重试")
+	}
+
+	// 4️⃣ 重新排期（写入失败时由补偿任务按 MySQL 中的时间处理）
+	if err := dao.AutoConfirmQueue.Add(ctx, orderNo, autoConfirmTime); err != nil {
+		log.Printf("❌ 延长收货重新排期失败：%s, 错误：%v", orderNo, err)
+	}
+
+	return &vo.ExtendConfirmResp{OrderNo: orderNo, AutoConfirmTime: autoConfirmTime}, nil
+}
+
+// AutoConfirmOrder 到期自动确认收货（已确认、已延长未到期的订单跳过）
+func (s *OrderService) AutoConfirmOrder(orderNo string) error {
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if order.OrderStatus != constants.ORDER_STATUS_SHIPPED || order.AutoConfirmTime == nil {
+		return nil
+	}
+	// 用户延长了收货：按新的时间重新排期
+	if order.AutoConfirmTime.After(time.Now()) {
+		return dao.AutoConfirmQueue.Add(ctx, orderNo, *order.AutoConfirmTime)
+	}
+
+	if err := completeOrder(order); err != nil {
+		return err
+	}
+	log.Printf("✅ 订单已自动确认收货：%s", orderNo)
+	return nil
+}
+
+// ConfirmOverdueOrders 兜底确认已到期仍未确认收货的订单（延迟队列丢失时以 MySQL 为准）
+func (s *OrderService) ConfirmOverdueOrders() {
+	before := time.Now().Add(-overdueOrderGrace)
+	confirmed, failed := 0, 0
+	for {
+		orderNums, err := dao.Order.GetAutoConfirmDueOrderNums(before, autoConfirmBatch)
+		if err != nil {
+			log.Printf("❌ 自动确认收货补偿：查询到期订单失败: %v", err)
+			return
+		}
+		for _, orderNo := range orderNums {
+			if err := s.AutoConfirmOrder(orderNo); err != nil {
+				log.Printf("❌ 自动确认收货补偿失败：%s, 错误：%v", orderNo, err)
+				failed++
+				continue
+			}
+			dao.AutoConfirmQueue.Remove(ctx, orderNo)
+			confirmed++
+		}
+		if len(orderNums) < autoConfirmBatch || failed > 0 {
+			break
+		}
+	}
+	if confirmed > 0 || failed > 0 {
+		log.Printf("🧹 自动确认收货补偿：确认 %d 个订单，失败 %d 个", confirmed, failed)
+	}
+}
@@ -2,15 +2,20 @@ package userService
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
+	"xiaomi-mall/config"
 	"xiaomi-mall/internal/api/dto"
 	"xiaomi-mall/internal/api/vo"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/orderevent"
+	"xiaomi-mall/internal/pkg/pricing"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
 	"xiaomi-mall/pkg/idgen"
 	"xiaomi-mall/pkg/xerr"
 
-	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
@@ -44,11 +49,28 @@ func (OrderService) CreateOrder(userID uint, req dto.CreateOrderReq) (resp *vo.C
 		if !exists {
 			return nil, xerr.NewErrMsg("商品不存在")
 		}
-		if sku.Stock < item.Num {
+		if sku.Stock-sku.Reserved < item.Num {
+			outOfStockCounter.IncLabel(skuLabel(sku.ID))
 			return nil, xerr.NewErrMsg("库存不足")
 		}
 	}
 
+	// 2.1 校验商品已上架（下架商品不能通过 SKU ID 直接下单）
+	productIDs := make([]uint, 0, len(skus))
+	for _, sku := range skus {
+		productIDs = append(productIDs, sku.ProductID)
+	}
+	products, err := dao.Product.GetProductsByIDs(productIDs)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	for _, sku := range skus {
+		product, ok := products[sku.ProductID]
+		if !ok || !product.OnSale {
+			return nil, xerr.NewErrCode(xerr.PRODUCT_OFF_SALE)
+		}
+	}
+
 	// ========== 【事务外】Step 3: 查询用户地址 ==========
 	address, err := dao.Address.GetByID(req.AddressID)
 	if err != nil {
@@ -58,10 +80,14 @@ func (OrderService) CreateOrder(userID uint, req dto.CreateOrderReq) (resp *vo.C
 		return nil, xerr.NewErrMsg("地址不属于当前用户")
 	}
 
-	// ========== 【事务外】Step 4: 计算订单总金额 ==========
+	// ========== 【事务外】Step 4: 计算订单总金额（按下单时刻的实际售价）==========
+	quotes, err := pricing.ResolveSkus(skus, time.Now())
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
 	totalAmount := int64(0)
 	for _, sku := range req.Items {
-		totalAmount += skuMap[sku.SkuID].Price * int64(sku.Num)
+		totalAmount += quotes[sku.SkuID].Price * int64(sku.Num)
 	}
 	// ========== 【事务外】Step 5: 生成订单号（雪花算法）==========
 	orderNum := idgen.GenStringID()
@@ -82,7 +108,7 @@ func (OrderService) CreateOrder(userID uint, req dto.CreateOrderReq) (resp *vo.C
 		OrderStatus:     0,
 		Type:            1, // 普通订单
 		AddressSnapshot: string(addressJSON),
-		ExpireTime:      time.Now().Add(30 * time.Minute),
+		ExpireTime:      orderExpireTime(constants.ORDER_TYPE_NORMAL),
 		Remark:          req.Remark,
 		Version:         0,
 	}
@@ -96,53 +122,53 @@ func (OrderService) CreateOrder(userID uint, req dto.CreateOrderReq) (resp *vo.C
 			ProductID:    sku.ProductID,
 			ProductSkuID: sku.ID,
 			Num:          item.Num,
-			Price:        sku.Price,
+			Price:        quotes[sku.ID].Price, // 成交价快照
 			Title:        sku.Title,
 			ImgPath:      sku.ImgPath,
 		})
 	}
 
-	// ========== 【事务内】Step 7: 开启数据库事务 ==========
-	err = dao.DB.Transaction(func(tx *gorm.DB) error {
-		// 7.1 乐观锁扣减库存
-		for _, item := range req.Items {
-			sku := skuMap[item.SkuID]
-			rowsAffected, err := dao.Product.DecrementStock(tx, item.SkuID, item.Num, sku.Version)
-			if err != nil {
-				return err // 库存不足或版本冲突，回滚
-			}
-			if rowsAffected == 0 {
-				return xerr.NewErrMsg("库存不足")
-			}
-		}
+	// ========== 【事务内】Step 7: 开启数据库事务（乐观锁冲突时退避重试） ==========
+	for attempt := 0; ; attempt++ {
+		err = createOrderTx(order, orderItems, req.Items, skuMap)
 
-		// 7.2 创建订单
-		if err := dao.Order.CreateOrder(tx, order, orderItems); err != nil {
-			return err
+		var conflict *stockConflictError
+		if !errors.As(err, &conflict) {
+			break
+		}
+		if attempt >= config.AppConfig.Order.ReserveRetries {
+			reserveExhaustedCounter.IncLabel(skuLabel(conflict.skuID))
+			return nil, xerr.NewErrMsg("当前购买人数较多，请稍后重试")
 		}
 
-		// 7.3 清空购物车（可选）
-		// if req.FromCart {
-		//     tx.Where("user_id = ? AND sku_id IN ?", userID, skuIDs).
-		//       Delete(&model.Cart{})
-		// }
-
-		return nil
-	})
+		// 退避后重新读取冲突 SKU 的版本号，整单重试
+		reserveRetryCounter.Inc()
+		time.Sleep(reserveBackoff(attempt))
+		latest, err := dao.Product.GetSkuByID(conflict.skuID)
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+		skuMap[latest.ID] = latest
+	}
 
 	// ========== 检查事务是否成功 ==========
 	if err != nil {
 		return nil, err
 	}
+	reserveSuccessCounter.Add(int64(len(req.Items)))
 
-	// ========== 【事务后】Step 8: 加入延迟队列 ==========
-	score := float64(order.ExpireTime.Unix())
-	dao.Rdb.ZAdd(ctx, "order:delay:queue", &redis.Z{
-		Score:  score,
-		Member: orderNum,
-	})
+	// ========== 【事务后】Step 8: 同步库存镜像 + 低库存检查 ==========
+	deltas := make(map[uint]int, len(req.Items))
+	for _, item := range req.Items {
+		deltas[item.SkuID] -= item.Num
+	}
+	stockalert.Apply(ctx, deltas)
 
-	// ========== 【事务后】Step 9: 返回订单信息 ==========
+	// ========== 【事务后】Step 9: 加入延迟队列（超时关单 + 待支付提醒）==========
+	// 写入失败时由超时关单补偿任务在到期后兜底关单
+	scheduleOrderTimeout(orderNum, order.ExpireTime)
+
+	// ========== 【事务后】Step 10: 返回订单信息 ==========
 	return &vo.CreateOrderResp{
 		OrderNo:     orderNum,
 		TotalAmount: totalAmount,
@@ -187,6 +213,7 @@ func (s *OrderService) PayOrder(userID uint, req dto.PayOrderReq) (*vo.PayOrderR
 	tradeNo := generateMockTradeNo(req.PayType) // 模拟交易流水号
 
 	// ========== Step 5: 更新订单状态（事务 + 乐观锁） ==========
+	var event *orderevent.Event
 	err = dao.DB.Transaction(func(tx *gorm.DB) error {
 		// 使用 DAO 层封装的支付方法（包含乐观锁）
 		rowsAffected, err := dao.Order.PayOrder(
@@ -202,16 +229,30 @@ func (s *OrderService) PayOrder(userID uint, req dto.PayOrderReq) (*vo.PayOrderR
 		if rowsAffected == 0 {
 			return xerr.NewErrMsg("订单状态已变更，请刷新后重试")
 		}
-		return nil
+
+		// 预占库存转为实际扣减（记录库存流水）
+		if _, err := dao.Reservation.Convert(tx, orderNo, dao.UserOperator(userID)); err != nil {
+			return err
+		}
+
+		// 发布支付事件（销量等在同一事务内更新）
+		items, err := dao.Order.GetOrderItems(tx, orderNo)
+		if err != nil {
+			return err
+		}
+		event = orderevent.New(orderevent.Paid, order, items)
+		return orderevent.PublishTx(tx, event)
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	orderevent.Publish(event)
 
 	// ========== Step 6: 从延迟队列移除 ==========
 	// 已支付的订单不需要自动关闭
-	dao.Rdb.ZRem(ctx, "order:delay:queue", orderNo)
+	dao.OrderTimeoutQueue.Remove(ctx, orderNo)
+	dao.PayReminderQueue.Remove(ctx, orderNo)
 
 	// ========== Step 7: 返回支付结果 ==========
 	return &vo.PayOrderResp{
@@ -240,18 +281,15 @@ func (s *OrderService) CloseOrder(orderNo string) error {
 		return err
 	}
 
-	// 2️⃣ 只关闭未支付的订单
-	if order.PayStatus != 0 {
-		return nil // 已支付，跳过
+	// 2️⃣ 只关闭待支付的订单（已支付、已取消的跳过，重复关单不会重复回补库存）
+	if order.OrderStatus != 0 || order.PayStatus != 0 {
+		return nil
 	}
-	return dao.DB.Transaction(func(tx *gorm.DB) error {
-		// 3️⃣ 更新订单状态（乐观锁）
-		rowsAffected, err := dao.Order.UpdateOrderStatus(
-			tx,
-			orderNo,
-			4, // OrderStatus = 4 (已取消)
-			order.Version,
-		)
+	var items []*model.OrderItem
+	var restored map[uint]int
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		// 3️⃣ 更新订单状态（乐观锁，只更新待支付的订单）
+		rowsAffected, err := dao.Order.CloseOrder(tx, orderNo, order.Version)
 
 		if err != nil {
 			return err
@@ -261,23 +299,24 @@ func (s *OrderService) CloseOrder(orderNo string) error {
 			return xerr.NewErrMsg("订单状态已变更")
 		}
 
-		// 4️⃣ 回滚库存
-		items, err := dao.Order.GetOrderItems(orderNo)
+		// 4️⃣ 释放库存
+		items, err = dao.Order.GetOrderItems(tx, orderNo)
 		if err != nil {
 			return err
 		}
+		restored, err = restoreOrderStock(tx, orderNo, items, constants.STOCK_REASON_TIMEOUT, constants.STOCK_OPERATOR_SYSTEM)
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-		for _, item := range items {
-			tx.Model(&model.ProductSku{}).
-				Where("id = ?", item.ProductSkuID).
-				Updates(map[string]interface{}{
-					"stock":   gorm.Expr("stock + ?", item.Num),
-					"version": gorm.Expr("version + 1"),
-				})
-		}
+	// 5️⃣ 同步库存镜像 + 到货提醒 / 告警恢复
+	stockalert.Apply(ctx, restored)
 
-		return nil
-	})
+	// 6️⃣ 发布取消事件
+	orderevent.Publish(orderevent.New(orderevent.Cancelled, order, items))
+	return nil
 }
 
 // 取消订单
@@ -292,7 +331,19 @@ func (s *OrderService) CancelOrder(userID uint, req dto.CancelOrderReq) error {
 		return xerr.NewErrMsg("订单不属于当前用户")
 	}
 
-	return dao.DB.Transaction(func(tx *gorm.DB) error {
+	// 2️⃣ 只能取消待支付的订单（已支付的订单走退款）
+	if order.OrderStatus != 0 || order.PayStatus != 0 {
+		return xerr.NewErrMsg("只能取消待支付的订单")
+	}
+
+	// 秒杀订单没有库存预占，库存在秒杀活动中，走秒杀关单流程
+	if order.Type == 2 {
+		return Seckill.CloseSeckillOrder(orderNo, dao.UserOperator(userID))
+	}
+
+	var items []*model.OrderItem
+	var restored map[uint]int
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
 		// 3️⃣ 更新订单状态（乐观锁）
 		rowsAffected, err := dao.Order.UpdateOrderStatus(
 			tx,
@@ -309,23 +360,53 @@ func (s *OrderService) CancelOrder(userID uint, req dto.CancelOrderReq) error {
 			return xerr.NewErrMsg("订单状态已变更")
 		}
 
-		// 4️⃣ 回滚库存
-		items, err := dao.Order.GetOrderItems(orderNo)
+		// 4️⃣ 释放库存
+		items, err = dao.Order.GetOrderItems(tx, orderNo)
 		if err != nil {
 			return err
 		}
+		restored, err = restoreOrderStock(tx, orderNo, items, constants.STOCK_REASON_CANCEL, dao.UserOperator(userID))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 5️⃣ 同步库存镜像 + 到货提醒 / 告警恢复
+	stockalert.Apply(ctx, restored)
+
+	// 6️⃣ 发布取消事件
+	orderevent.Publish(orderevent.New(orderevent.Cancelled, order, items))
+	return nil
+}
 
-		for _, item := range items {
-			tx.Model(&model.ProductSku{}).
-				Where("id = ?", item.ProductSkuID).
-				Updates(map[string]interface{}{
-					"stock":   gorm.Expr("stock + ?", item.Num),
-					"version": gorm.Expr("version + 1"),
-				})
+// restoreOrderStock 取消 / 超时关单时归还订单占用的库存，返回各 SKU 恢复的可售库存（用于同步镜像）
+// 有预占记录的订单释放预占（重复调用无副作用）；预占上线前创建的旧订单库存已扣减，按订单明细回补
+func restoreOrderStock(tx *gorm.DB, orderNo string, items []*model.OrderItem, reason, operator string) (map[uint]int, error) {
+	restored := make(map[uint]int, len(items))
+
+	reservations, err := dao.Reservation.GetByOrder(tx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) > 0 {
+		released, err := dao.Reservation.Release(tx, orderNo)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range released {
+			restored[r.ProductSkuID] += r.Quantity
 		}
+		return restored, nil
+	}
 
-		return nil
-	})
+	for _, item := range items {
+		if err := dao.Product.IncrementStock(tx, item.ProductSkuID, item.Num, reason, orderNo, operator); err != nil {
+			return nil, err
+		}
+		restored[item.ProductSkuID] += item.Num
+	}
+	return restored, nil
 }
 
 // 订单详情查询
@@ -339,11 +420,19 @@ func (s *OrderService) GetOrderDetail(req dto.OrderDetailReq) (*vo.OrderDetailRe
 	}
 
 	// ========== Step 2: 查询订单商品列表 ==========
-	orderItems, err := dao.Order.GetOrderItems(orderNo)
+	orderItems, err := dao.Order.GetOrderItems(dao.DB, orderNo)
 	if err != nil {
 		return nil, xerr.NewErrCode(xerr.DB_ERROR)
 	}
 
+	// 已评价的订单商品（只有已完成的订单才可能有评价）
+	reviewed := make(map[uint]bool)
+	if order.OrderStatus == 3 {
+		if reviewed, err = dao.Review.GetReviewedItemIDs(orderNo); err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+	}
+
 	// ========== Step 3: 解析地址快照 ==========
 	var addressSnapshot vo.AddressSnapshotVO
 	if err := json.Unmarshal([]byte(order.AddressSnapshot), &addressSnapshot); err != nil {
@@ -355,6 +444,7 @@ func (s *OrderService) GetOrderDetail(req dto.OrderDetailReq) (*vo.OrderDetailRe
 	items := make([]vo.OrderDetailItemVO, 0, len(orderItems))
 	for _, item := range orderItems {
 		items = append(items, vo.OrderDetailItemVO{
+			ItemID:       item.ID,
 			ProductID:    item.ProductID,
 			ProductSkuID: item.ProductSkuID,
 			Title:        item.Title,
@@ -362,6 +452,7 @@ func (s *OrderService) GetOrderDetail(req dto.OrderDetailReq) (*vo.OrderDetailRe
 			Price:        item.Price,
 			Num:          item.Num,
 			Subtotal:     item.Price * int64(item.Num), // 小计 = 单价 * 数量
+			Reviewed:     reviewed[item.ID],
 		})
 	}
 
@@ -384,6 +475,10 @@ func (s *OrderService) GetOrderDetail(req dto.OrderDetailReq) (*vo.OrderDetailRe
 		CancelTime: order.CancelTime,
 		ExpireTime: order.ExpireTime,
 
+		// 自动确认收货
+		AutoConfirmTime: order.AutoConfirmTime,
+		ConfirmExtended: order.ConfirmExtended,
+
 		// 收货地址
 		Address: addressSnapshot,
 
@@ -423,7 +518,7 @@ func (s *OrderService) GetOrderList(userID uint, req dto.OrderListReq) (*vo.Orde
 	list := make([]vo.OrderItemVO, 0, len(orders))
 	for _, order := range orders {
 		// 查询该订单的商品列表（用于获取第一个商品和总数量）
-		orderItems, err := dao.Order.GetOrderItems(order.OrderNum)
+		orderItems, err := dao.Order.GetOrderItems(dao.DB, order.OrderNum)
 		if err != nil {
 			continue // 跳过异常订单
 		}
@@ -502,17 +597,41 @@ func (s *OrderService) ConfirmOrder(userID uint, req dto.ConfirmOrderReq) error
 		return xerr.NewErrMsg("订单状态异常")
 	}
 
-	// ========== Step 4: 更新订单状态（事务 + 乐观锁） ==========
-	err = dao.DB.Transaction(func(tx *gorm.DB) error {
-		rowsAffected, err := dao.Order.ConfirmOrder(tx, orderNo, order.Version)
+	// ========== Step 4: 更新订单状态并发布完成事件 ==========
+	if err := completeOrder(order); err != nil {
+		return err
+	}
+
+	// ========== Step 5: 取消自动确认 ==========
+	dao.AutoConfirmQueue.Remove(ctx, orderNo)
+	return nil
+}
+
+// completeOrder 确认收货（用户确认 / 到期自动确认共用）：乐观锁更新为已完成并发布完成事件
+func completeOrder(order *model.Order) error {
+	// 1️⃣ 更新订单状态（事务 + 乐观锁）
+	var event *orderevent.Event
+	err := dao.DB.Transaction(func(tx *gorm.DB) error {
+		rowsAffected, err := dao.Order.ConfirmOrder(tx, order.OrderNum, order.Version)
 		if err != nil {
 			return err
 		}
 		if rowsAffected == 0 {
 			return xerr.NewErrMsg("订单状态已变更，请刷新后重试")
 		}
-		return nil
+
+		items, err := dao.Order.GetOrderItems(tx, order.OrderNum)
+		if err != nil {
+			return err
+		}
+		event = orderevent.New(orderevent.Completed, order, items)
+		return orderevent.PublishTx(tx, event)
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// 2️⃣ 发布完成事件（解锁评价等）
+	orderevent.Publish(event)
+	return nil
 }
@@ -0,0 +1,93 @@
+package userService
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"time"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/metrics"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+// 下单预占库存的计数器（区分真实缺货和并发冲突，按 SKU 细分）
+var (
+	reserveSuccessCounter   = metrics.NewCounter("order_stock_reserved_total", "下单预占库存成功的 SKU 行数")
+	reserveConflictCounter  = metrics.NewCounter("order_stock_conflict_total", "预占库存时乐观锁版本冲突的次数（库存充足）")
+	reserveRetryCounter     = metrics.NewCounter("order_stock_retry_total", "因版本冲突重试下单事务的次数")
+	reserveExhaustedCounter = metrics.NewCounter("order_stock_retry_exhausted_total", "重试耗尽仍冲突、下单失败的次数")
+	outOfStockCounter       = metrics.NewCounter("order_stock_out_of_stock_total", "可售库存不足导致下单失败的次数")
+)
+
+// stockConflictError 预占库存时版本冲突（事务回滚，由 CreateOrder 重新读取版本号后重试）
+type stockConflictError struct {
+	skuID uint
+}
+
+func (e *stockConflictError) Error() string {
+	return "库存版本冲突: sku " + skuLabel(e.skuID)
+}
+
+// createOrderTx 下单事务：预占库存 + 创建订单
+func createOrderTx(order *model.Order, orderItems []*model.OrderItem, items []dto.OrderItemReq, skuMap map[uint]*model.ProductSku) error {
+	return dao.DB.Transaction(func(tx *gorm.DB) error {
+		// 1️⃣ 预占库存（支付时才真正扣减，预占到期时间与订单一致）
+		for _, item := range items {
+			sku := skuMap[item.SkuID]
+			result, err := dao.Reservation.Reserve(tx, &model.StockReservation{
+				OrderNum:     order.OrderNum,
+				ProductID:    sku.ProductID,
+				ProductSkuID: sku.ID,
+				Quantity:     item.Num,
+				ExpireTime:   order.ExpireTime,
+			}, sku)
+			if err != nil {
+				return err
+			}
+			switch result {
+			case dao.ReserveConflict:
+				reserveConflictCounter.IncLabel(skuLabel(sku.ID))
+				return &stockConflictError{skuID: sku.ID}
+			case dao.ReserveOutOfStock:
+				outOfStockCounter.IncLabel(skuLabel(sku.ID))
+				return xerr.NewErrMsg("库存不足")
+			}
+		}
+
+		// 2️⃣ 创建订单
+		if err := dao.Order.CreateOrder(tx, order, orderItems); err != nil {
+			return err
+		}
+
+		// 3️⃣ 清空购物车（可选）
+		// if req.FromCart {
+		//     tx.Where("user_id = ? AND sku_id IN ?", userID, skuIDs).
+		//       Delete(&model.Cart{})
+		// }
+
+		return nil
+	})
+}
+
+// reserveBackoff 第 attempt 次重试前的等待时间：指数退避 + 随机抖动，避免冲突的请求同时重试
+func reserveBackoff(attempt int) time.Duration {
+	base := time.Duration(config.AppConfig.Order.ReserveBackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(config.AppConfig.Order.ReserveMaxBackoffMs) * time.Millisecond
+	if base <= 0 {
+		return 0
+	}
+	backoff := base << attempt
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int64N(int64(backoff/2)+1))
+}
+
+// skuLabel 计数器的 SKU 标签
+func skuLabel(skuID uint) string {
+	return strconv.FormatUint(uint64(skuID), 10)
+}
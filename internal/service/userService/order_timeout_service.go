@@ -0,0 +1,170 @@
+package userService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/delayqueue"
+	"xiaomi-mall/internal/pkg/types"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm"
+)
+
+const (
+	overdueOrderGrace = 2 * time.Minute // 到期后留给延迟队列正常关单的时间
+	overdueOrderBatch = 100
+	rebuildQueueBatch = 500
+)
+
+// orderExpireTime 按订单类型计算支付截止时间（订单表、延迟队列、秒杀队列数据统一使用这个时间）
+func orderExpireTime(orderType int) time.Time {
+	return time.Now().Add(config.AppConfig.Order.PayTimeout(orderType))
+}
+
+// payReminderTime 待支付提醒时间（截止前 N 分钟），不需要提醒时返回 false
+func payReminderTime(expireTime time.Time) (time.Time, bool) {
+	minutes := config.AppConfig.Order.PayReminderMinutes
+	if minutes <= 0 {
+		return time.Time{}, false
+	}
+	remindAt := expireTime.Add(-time.Duration(minutes) * time.Minute)
+	return remindAt, remindAt.After(time.Now())
+}
+
+// scheduleOrderTimeout 下单后加入延迟队列：到期关单，截止前提醒支付
+func scheduleOrderTimeout(orderNo string, expireTime time.Time) {
+	if err := dao.OrderTimeoutQueue.Add(ctx, orderNo, expireTime); err != nil {
+		log.Printf("❌ 订单加入超时队列失败：%s, 错误：%v（由超时关单补偿任务兜底）", orderNo, err)
+	}
+
+	if remindAt, ok := payReminderTime(expireTime); ok {
+		if err := dao.PayReminderQueue.Add(ctx, orderNo, remindAt); err != nil {
+			log.Printf("❌ 订单加入支付提醒队列失败：%s, 错误：%v", orderNo, err)
+		}
+	}
+}
+
+// SendPayReminder 提醒用户订单即将超时（订单已支付或已取消时跳过）
+func (s *OrderService) SendPayReminder(orderNo string) error {
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		// 秒杀订单尚未落库或脏数据，不再提醒
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if order.OrderStatus != constants.ORDER_STATUS_PENDING || order.PayStatus != constants.PAY_STATUS_UNPAID {
+		return nil
+	}
+	left := time.Until(order.ExpireTime)
+	if left <= 0 {
+		return nil // 已超时，等待关单
+	}
+
+	payload, _ := json.Marshal(types.PayReminderPayload{
+		OrderNum:   order.OrderNum,
+		AllPrice:   order.AllPrice,
+		ExpireTime: order.ExpireTime,
+	})
+	minutes := int(left.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return dao.Notification.BatchCreate([]*model.Notification{{
+		UserID:  order.UserID,
+		Type:    constants.NOTIFICATION_PAY_REMINDER,
+		Title:   "订单即将超时",
+		Content: fmt.Sprintf("您的订单 %s 还有 %d 分钟将自动取消，请尽快完成支付", order.OrderNum, minutes),
+		Payload: string(payload),
+	}})
+}
+
+// CloseExpiredOrder 关闭一个超时未支付的订单（按订单类型走普通订单 / 秒杀订单的关单流程）
+func (s *OrderService) CloseExpiredOrder(orderNo string) error {
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil {
+		// 订单不存在（秒杀订单尚未落库或脏数据），跳过
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("⚠️ 超时关单：订单不存在 %s", orderNo)
+			return nil
+		}
+		return err
+	}
+
+	if order.Type == 2 { // 秒杀订单
+		log.Printf("⏰ 发现过期秒杀订单：%s", orderNo)
+		return Seckill.CloseSeckillOrder(orderNo, constants.STOCK_OPERATOR_SYSTEM)
+	}
+	log.Printf("⏰ 发现过期普通订单：%s", orderNo)
+	return s.CloseOrder(orderNo)
+}
+
+// CloseOverdueOrders 兜底关闭已过期仍待支付的订单（Redis 延迟队列丢失或写入失败时，以 MySQL 为准）
+func (s *OrderService) CloseOverdueOrders() {
+	before := time.Now().Add(-overdueOrderGrace)
+	closed, failed := 0, 0
+	for {
+		orderNums, err := dao.Order.GetOverdueOrderNums(before, overdueOrderBatch)
+		if err != nil {
+			log.Printf("❌ 超时关单补偿：查询过期订单失败: %v", err)
+			return
+		}
+		for _, orderNo := range orderNums {
+			if err := s.CloseExpiredOrder(orderNo); err != nil {
+				log.Printf("❌ 超时关单补偿失败：%s, 错误：%v", orderNo, err)
+				failed++
+				continue
+			}
+			dao.OrderTimeoutQueue.Remove(ctx, orderNo)
+			closed++
+		}
+		// 不足一批说明已处理完；有失败时结束本轮，避免反复查到同一批失败订单
+		if len(orderNums) < overdueOrderBatch || failed > 0 {
+			break
+		}
+	}
+	if closed > 0 || failed > 0 {
+		log.Printf("🧹 超时关单补偿：关闭 %d 个订单，失败 %d 个", closed, failed)
+	}
+}
+
+// RebuildDelayQueue 按 MySQL 中的待支付订单重建超时队列和支付提醒队列（只补充缺失的订单，不覆盖已有的排期）
+func (s *OrderService) RebuildDelayQueue() (int, error) {
+	var lastID uint
+	added := 0
+	for {
+		orders, err := dao.Order.GetPendingOrdersAfter(lastID, rebuildQueueBatch)
+		if err != nil {
+			return added, err
+		}
+		if len(orders) == 0 {
+			return added, nil
+		}
+
+		members := make([]delayqueue.Item, 0, len(orders))
+		reminders := make([]delayqueue.Item, 0, len(orders))
+		for _, order := range orders {
+			members = append(members, delayqueue.Item{Member: order.OrderNum, At: order.ExpireTime})
+			if remindAt, ok := payReminderTime(order.ExpireTime); ok {
+				reminders = append(reminders, delayqueue.Item{Member: order.OrderNum, At: remindAt})
+			}
+		}
+		n, err := dao.OrderTimeoutQueue.AddNX(ctx, members...)
+		if err != nil {
+			return added, err
+		}
+		added += int(n)
+		// 支付提醒只补充还没到提醒时间的订单
+		if _, err := dao.PayReminderQueue.AddNX(ctx, reminders...); err != nil {
+			return added, err
+		}
+		lastID = orders[len(orders)-1].ID
+	}
+}
@@ -3,14 +3,21 @@ package userService
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 	"xiaomi-mall/internal/api/dto"
 	"xiaomi-mall/internal/api/vo"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/internal/pkg/pricing"
+	"xiaomi-mall/internal/pkg/types"
 	pkgBloom "xiaomi-mall/pkg/bloom"
 	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
 )
 
 type ProductService struct{}
@@ -59,17 +66,7 @@ func (s *ProductService) ProductList(req dto.ProductListReq) (*vo.ProductListRes
 	// ========== 3️⃣ 转换为 VO ==========
 	productVOs := make([]vo.ProductItemVO, 0, len(products))
 	for _, product := range products {
-		productVOs = append(productVOs, vo.ProductItemVO{
-			ProductID:     product.ID,
-			Name:          product.Name,
-			Title:         product.Title,
-			ImgPath:       product.ImgPath,
-			Price:         product.Price,
-			DiscountPrice: product.DiscountPrice,
-			Num:           product.Num,
-			ClickNum:      product.ClickNum,
-			OnSale:        product.OnSale,
-		})
+		productVOs = append(productVOs, toProductItemVO(product))
 	}
 
 	// ========== 4️⃣ 返回响应 ==========
@@ -82,8 +79,24 @@ func (s *ProductService) ProductList(req dto.ProductListReq) (*vo.ProductListRes
 	return resp, nil
 }
 
+// 商品详情缓存配置
+var (
+	productDetailCacheOpts = cache.Options{
+		TTL:     time.Hour,
+		Jitter:  10 * time.Minute,
+		NullTTL: 5 * time.Minute,
+		Local:   true,
+	}
+	skuDetailCacheOpts = cache.Options{
+		TTL:     10 * time.Minute, // SKU 变化频繁，缓存时间设置短一些
+		Jitter:  2 * time.Minute,
+		NullTTL: 5 * time.Minute,
+		Local:   true,
+	}
+)
+
 // 商品详情查询
-func (s *ProductService) ProductDetail(req dto.ProductDetailReq) (*vo.ProductDetailResp, error) {
+func (s *ProductService) ProductDetail(userID uint, req dto.ProductDetailReq) (*vo.ProductDetailResp, error) {
 	// ========== 0️⃣ 布隆过滤器前置校验（防止缓存穿透）==========
 	if pkgBloom.ProductBloom != nil {
 		if !pkgBloom.ProductBloom.TestUint(req.ProductID) {
@@ -93,62 +106,136 @@ func (s *ProductService) ProductDetail(req dto.ProductDetailReq) (*vo.ProductDet
 		}
 	}
 
-	// ========== 1️⃣ 尝试从缓存读取 ==========
-	cacheKey := fmt.Sprintf("product:detail:%d", req.ProductID)
-	cacheData, err := dao.Rdb.Get(ctx, cacheKey).Result()
-	if err == nil {
-		// 反序列化缓存数据
-		var resp vo.ProductDetailResp
-		if err := json.Unmarshal([]byte(cacheData), &resp); err == nil {
-			println("✅ 商品详情：命中缓存") // 调试日志
-			return &resp, nil
+	// ========== 1️⃣ 读缓存（未命中时合并回源） ==========
+	// 热点商品（秒杀商品）使用逻辑过期，过期期间返回旧值并异步刷新，避免击穿
+	opts := productDetailCacheOpts
+	opts.LogicalExpire = dao.Product.IsHotProduct(ctx, req.ProductID)
+
+	resp, err := cache.Fetch(ctx, cache.ProductDetailKey(req.ProductID), opts, func() (*vo.ProductDetailResp, error) {
+		return s.loadProductDetail(req.ProductID)
+	})
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+		}
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	// ========== 2️⃣ 填充实时库存（库存不进缓存） ==========
+	skuIDs := make([]uint, 0, len(resp.SKUs))
+	for _, sku := range resp.SKUs {
+		skuIDs = append(skuIDs, sku.SkuID)
+	}
+	stocks, err := loadSkuStocks(skuIDs)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	for i := range resp.SKUs {
+		resp.SKUs[i].Stock = stocks[resp.SKUs[i].SkuID]
+	}
+
+	// ========== 3️⃣ 当前用户是否已收藏（按用户区分，不进缓存） ==========
+	if userID > 0 {
+		isFavorite, err := dao.Favorite.IsFavorite(ctx, userID, req.ProductID)
+		if err != nil {
+			log.Printf("⚠️  查询收藏状态失败: user=%d product=%d, 错误: %v", userID, req.ProductID, err)
 		}
-		// JSON 解析失败，删除损坏的缓存
-		dao.Rdb.Del(ctx, cacheKey)
+		resp.IsFavorite = isFavorite
 	}
 
+	// ========== 4️⃣ 记录浏览（命中缓存也计数，由后台任务批量落库） ==========
+	recordProductView(req.ProductID, userID)
+
+	return resp, nil
+}
+
+// recordProductView 记录商品浏览：点击量增量、每日浏览量、每日独立访客、用户浏览历史
+func recordProductView(productID, userID uint) {
+	now := time.Now()
+	viewer := ""
+	if userID > 0 {
+		viewer = fmt.Sprintf("u:%d", userID)
+	}
+	day := now.Format("20060102")
+	if err := dao.View.RecordView(ctx, productID, viewer, day); err != nil {
+		// 统计失败不影响商品展示
+		log.Printf("⚠️  记录商品浏览失败: %d, 错误: %v", productID, err)
+	}
+
+	if userID > 0 {
+		if err := dao.History.RecordHistory(ctx, userID, productID, now); err != nil {
+			log.Printf("⚠️  记录浏览历史失败: user=%d product=%d, 错误: %v", userID, productID, err)
+		}
+	}
+}
+
+// loadProductDetail 从数据库加载商品详情（缓存回源）
+func (s *ProductService) loadProductDetail(productID uint) (*vo.ProductDetailResp, error) {
 	println("⚠️  商品详情：缓存未命中，查询数据库") // 调试日志
 
-	// ========== 2️⃣ 查询商品基本信息（SPU） ==========
-	product, err := dao.Product.GetProductByID(req.ProductID)
+	// ========== 1️⃣ 查询商品基本信息（SPU） ==========
+	product, err := dao.Product.GetProductByID(productID)
 	if err != nil {
-		// 商品不存在，缓存空值防止缓存穿透
-		dao.Rdb.Set(ctx, cacheKey, "null", 5*time.Minute)
-		return nil, xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cache.ErrNotFound // 缓存空值防止缓存穿透
+		}
+		return nil, err
 	}
 
-	// ========== 3️⃣ 查询分类名称 ==========
+	// ========== 2️⃣ 查询分类名称 ==========
 	category, err := dao.Category.GetCategoryByID(product.CategoryID)
 	if err != nil {
 		// 分类不存在不影响商品展示，使用默认值
 		category = &model.Category{Name: "未分类"}
 	}
 
-	// ========== 4️⃣ 查询该商品的所有 SKU ==========
-	skus, err := dao.Product.GetSkusByProductID(req.ProductID)
+	// ========== 3️⃣ 查询该商品的所有 SKU ==========
+	skus, err := dao.Product.GetSkusByProductID(productID)
 	if err != nil {
-		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+		return nil, err
+	}
+
+	// 查询评分汇总（新增评价时会失效详情缓存）
+	ratings, err := dao.Review.GetRatingStats(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查询图集和图文详情（单独存储，只在详情页加载）
+	images, err := dao.Product.GetImages(productID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := dao.Product.GetContent(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 计算实际售价（价格规则开始 / 结束时由定时任务失效详情缓存）
+	quotes, err := pricing.ResolveSkus(skus, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	// 转换 SKU 为 VO（确保非 nil）
 	skuVOs := make([]vo.SkuVO, 0, len(skus))
 	for _, sku := range skus {
+		quote := quotes[sku.ID]
 		skuVOs = append(skuVOs, vo.SkuVO{
-			SkuID: sku.ID,
-			Title: sku.Title,
-			Price: sku.Price,
-			Stock: sku.Stock,
-			Code:  sku.Code,
+			SkuID:         sku.ID,
+			Title:         sku.Title,
+			Price:         quote.Price,
+			OriginalPrice: quote.BasePrice,
+			PriceEndTime:  quote.EndTime,
+			Code:          sku.Code,
+			Images:        nonNilImages(images[sku.ID]),
+			Rating:        toRatingVO(ratings[sku.ID]),
+			// Stock 不缓存，读取时从库存镜像填充
 		})
 	}
 
-	// ========== 5️⃣ 增加商品点击量（异步处理，不影响查询性能） ==========
-	go func() {
-		dao.Product.IncrementClickNum(req.ProductID)
-	}()
-
-	// ========== 6️⃣ 构造响应 VO ==========
-	resp := &vo.ProductDetailResp{
+	// ========== 4️⃣ 构造响应 VO ==========
+	return &vo.ProductDetailResp{
 		ProductID:     product.ID,
 		Name:          product.Name,
 		CategoryID:    product.CategoryID,
@@ -156,63 +243,186 @@ func (s *ProductService) ProductDetail(req dto.ProductDetailReq) (*vo.ProductDet
 		Title:         product.Title,
 		Info:          product.Info,
 		ImgPath:       product.ImgPath,
+		Images:        nonNilImages(images[0]),
 		Price:         product.Price,
 		DiscountPrice: product.DiscountPrice,
 		Num:           product.Num,
 		ClickNum:      product.ClickNum,
 		OnSale:        product.OnSale,
+		FavoriteNum:   product.FavoriteNum,
+		Rating:        toRatingVO(ratings[0]),
 		SKUs:          skuVOs, // ⬅️ 确保是 [] 而不是 null
+		Content:       decodeContentBlocks(content),
+	}, nil
+}
+
+// nonNilImages 确保图集返回 [] 而不是 null
+func nonNilImages(images []string) []string {
+	if images == nil {
+		return []string{}
+	}
+	return images
+}
+
+// decodeContentBlocks 解析图文详情 JSON（确保返回 [] 而不是 null）
+func decodeContentBlocks(data string) []types.ContentBlock {
+	blocks := make([]types.ContentBlock, 0)
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+			log.Printf("⚠️  解析图文详情失败: %v", err)
+			return []types.ContentBlock{}
+		}
 	}
+	return blocks
+}
 
-	// ========== 7️⃣ 写入缓存 ==========
-	if jsonData, err := json.Marshal(resp); err == nil {
-		dao.Rdb.Set(ctx, cacheKey, jsonData, time.Hour)
+// 关联商品（买了又买）
+func (s *ProductService) RelatedProducts(req dto.RelatedProductsReq) (*vo.RelatedProductsResp, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
 	}
 
-	return resp, nil
+	// ========== 1️⃣ 查询当前商品（兜底推荐需要分类） ==========
+	product, err := dao.Product.GetProductByID(req.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, xerr.NewErrCode(xerr.PRODUCT_NOT_FOUND)
+		}
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	list := make([]vo.ProductItemVO, 0, limit)
+	excludeIDs := []uint{product.ID}
+
+	// ========== 2️⃣ 共同购买的商品（Redis，多取一些以过滤下架商品） ==========
+	relatedIDs, err := dao.Recommend.GetRelated(ctx, product.ID, limit*2)
+	if err != nil {
+		log.Printf("⚠️  查询关联商品失败: %d, 错误: %v", product.ID, err)
+	}
+	if len(relatedIDs) > 0 {
+		products, err := dao.Product.GetProductsByIDs(relatedIDs)
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+		for _, id := range relatedIDs { // 按共同购买次数排序
+			related, ok := products[id]
+			if !ok || !related.OnSale {
+				continue
+			}
+			list = append(list, toProductItemVO(related))
+			excludeIDs = append(excludeIDs, id)
+			if len(list) >= limit {
+				break
+			}
+		}
+	}
+
+	// ========== 3️⃣ 不足时用同分类热销商品补齐 ==========
+	if len(list) < limit {
+		bestSellers, err := dao.Product.GetCategoryBestSellers(product.CategoryID, excludeIDs, limit-len(list))
+		if err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+		for _, p := range bestSellers {
+			list = append(list, toProductItemVO(p))
+		}
+	}
+
+	return &vo.RelatedProductsResp{List: list}, nil
+}
+
+// toProductItemVO 商品转列表项 VO
+func toProductItemVO(product *model.Product) vo.ProductItemVO {
+	return vo.ProductItemVO{
+		ProductID:     product.ID,
+		Name:          product.Name,
+		Title:         product.Title,
+		ImgPath:       product.ImgPath,
+		Price:         product.Price,
+		DiscountPrice: product.DiscountPrice,
+		Num:           product.Num,
+		ClickNum:      product.ClickNum,
+		OnSale:        product.OnSale,
+	}
 }
 
 // SKU详情查询
 func (s *ProductService) SkuDetail(req dto.SkuDetailReq) (*vo.SkuDetailResp, error) {
-	// ========== 1️⃣ 尝试从缓存读取 ==========
-	cacheKey := fmt.Sprintf("sku:detail:%d", req.SkuID)
-	cacheData, err := dao.Rdb.Get(ctx, cacheKey).Result()
-	if err == nil {
-
-		// 反序列化缓存数据
-		var resp vo.SkuDetailResp
-		if err := json.Unmarshal([]byte(cacheData), &resp); err == nil {
-			println("✅ SKU详情：命中缓存") // 调试日志
-			return &resp, nil
+	resp, err := cache.Fetch(ctx, cache.SkuDetailKey(req.SkuID), skuDetailCacheOpts, func() (*vo.SkuDetailResp, error) {
+		println("⚠️  SKU详情：缓存未命中，查询数据库") // 调试日志
+
+		sku, err := dao.Product.GetSkuByID(req.SkuID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, cache.ErrNotFound // SKU 不存在，缓存空值防止缓存穿透
+			}
+			return nil, err
 		}
-		// JSON 解析失败，删除损坏的缓存
-		dao.Rdb.Del(ctx, cacheKey)
+		product, err := dao.Product.GetProductByID(sku.ProductID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, cache.ErrNotFound
+			}
+			return nil, err
+		}
+		quotes, err := pricing.ResolveSkus([]*model.ProductSku{sku}, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		quote := quotes[sku.ID]
+		return &vo.SkuDetailResp{
+			SkuID:         sku.ID,
+			Title:         sku.Title,
+			Price:         quote.Price,
+			OriginalPrice: quote.BasePrice,
+			PriceEndTime:  quote.EndTime,
+			Code:          sku.Code,
+			OnSale:        product.OnSale,
+		}, nil
+	})
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+		}
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+	if !resp.OnSale {
+		return nil, xerr.NewErrCode(xerr.PRODUCT_OFF_SALE)
 	}
 
-	println("⚠️  SKU详情：缓存未命中，查询数据库") // 调试日志
-
-	// ========== 2️⃣ 查询 SKU 信息 ==========
-	sku, err := dao.Product.GetSkuByID(req.SkuID)
+	// 填充实时库存
+	stocks, err := loadSkuStocks([]uint{resp.SkuID})
 	if err != nil {
-		// SKU 不存在，缓存空值防止缓存穿透
-		dao.Rdb.Set(ctx, cacheKey, "null", 5*time.Minute)
-		return nil, xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
 	}
+	resp.Stock = stocks[resp.SkuID]
+
+	return resp, nil
+}
 
-	// ========== 3️⃣ 构造响应 VO ==========
-	resp := &vo.SkuDetailResp{
-		SkuID: sku.ID,
-		Title: sku.Title,
-		Price: sku.Price,
-		Stock: sku.Stock,
-		Code:  sku.Code,
+// loadSkuStocks 读取 SKU 实时库存：优先读 Redis 库存镜像，未命中的回源 MySQL 并补齐镜像
+func loadSkuStocks(skuIDs []uint) (map[uint]int, error) {
+	if len(skuIDs) == 0 {
+		return map[uint]int{}, nil
 	}
 
-	// ========== 4️⃣ 写入缓存 ==========
-	if jsonData, err := json.Marshal(resp); err == nil {
-		// SKU 库存变化频繁，缓存时间设置短一些
-		dao.Rdb.Set(ctx, cacheKey, jsonData, 10*time.Minute)
+	stocks, missing, err := dao.Stock.BatchGetSkuStocks(ctx, skuIDs)
+	if err != nil {
+		// Redis 异常，降级为直接查库
+		return dao.Product.GetSkuStocksByIDs(skuIDs)
+	}
+	if len(missing) == 0 {
+		return stocks, nil
 	}
 
-	return resp, nil
+	dbStocks, err := dao.Product.GetSkuStocksByIDs(missing)
+	if err != nil {
+		return nil, err
+	}
+	for skuID, stock := range dbStocks {
+		stocks[skuID] = stock
+		dao.Stock.SetSkuStock(ctx, skuID, stock)
+	}
+	return stocks, nil
 }
@@ -0,0 +1,75 @@
+package userService
+
+import (
+	"errors"
+	"log"
+	"time"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/stockalert"
+	"xiaomi-mall/pkg/constants"
+
+	"gorm.io/gorm"
+)
+
+const (
+	reservationSweepGrace = time.Minute // 到期后留给超时扫描器正常关单的时间
+	reservationSweepBatch = 100
+)
+
+// SweepExpiredReservations 兜底处理到期仍在预占中的库存（延迟队列丢失、关单失败、订单数据异常时）
+func (s *OrderService) SweepExpiredReservations() {
+	orderNums, err := dao.Reservation.GetExpiredOrderNums(time.Now().Add(-reservationSweepGrace), reservationSweepBatch)
+	if err != nil {
+		log.Printf("❌ 库存预占清扫：查询到期预占失败: %v", err)
+		return
+	}
+	for _, orderNo := range orderNums {
+		if err := s.sweepReservation(orderNo); err != nil {
+			log.Printf("❌ 库存预占清扫失败：%s, 错误：%v", orderNo, err)
+			continue
+		}
+		log.Printf("🧹 库存预占已清扫：%s", orderNo)
+	}
+}
+
+// sweepReservation 按订单当前状态处理一笔到期预占
+func (s *OrderService) sweepReservation(orderNo string) error {
+	order, err := dao.Order.GetOrderByOrderNum(orderNo)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// 1️⃣ 订单仍待支付：按超时关单（同时释放预占）
+	if err == nil && order.OrderStatus == 0 && order.PayStatus == 0 {
+		if err := s.CloseOrder(orderNo); err != nil {
+			return err
+		}
+		dao.OrderTimeoutQueue.Remove(ctx, orderNo)
+		return nil
+	}
+
+	// 2️⃣ 订单已支付（含已退款）：预占转为实际扣减
+	if err == nil && order.PayStatus != 0 {
+		return dao.DB.Transaction(func(tx *gorm.DB) error {
+			_, err := dao.Reservation.Convert(tx, orderNo, constants.STOCK_OPERATOR_SYSTEM)
+			return err
+		})
+	}
+
+	// 3️⃣ 订单不存在或已取消：释放预占并同步库存镜像
+	var released []*model.StockReservation
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		released, err = dao.Reservation.Release(tx, orderNo)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	deltas := make(map[uint]int, len(released))
+	for _, r := range released {
+		deltas[r.ProductSkuID] += r.Quantity
+	}
+	stockalert.Apply(ctx, deltas)
+	return nil
+}
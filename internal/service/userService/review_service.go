@@ -0,0 +1,198 @@
+package userService
+
+import (
+	"encoding/json"
+	"math"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/cache"
+	"xiaomi-mall/pkg/xerr"
+
+	"gorm.io/gorm"
+)
+
+type ReviewService struct{}
+
+var Review = new(ReviewService)
+
+// 发表评价（只能评价已完成订单中的商品，每个订单商品只能评价一次）
+func (s *ReviewService) CreateReview(userID uint, req dto.CreateReviewReq) (*vo.CreateReviewResp, error) {
+	// 1️⃣ 查询订单商品和订单
+	item, err := dao.Order.GetOrderItemByID(req.OrderItemID)
+	if err != nil {
+		return nil, xerr.NewErrMsg("订单商品不存在")
+	}
+	order, err := dao.Order.GetOrderByOrderNum(item.OrderNum)
+	if err != nil {
+		return nil, xerr.NewErrMsg("订单不存在")
+	}
+
+	// 2️⃣ 权限和状态校验
+	if order.UserID != userID {
+		return nil, xerr.NewErrMsg("订单不属于当前用户")
+	}
+	if order.OrderStatus != 3 {
+		return nil, xerr.NewErrMsg("订单未完成，暂不能评价")
+	}
+
+	// 3️⃣ 每个订单商品只能评价一次（order_item_id 唯一索引兜底并发）
+	exist, err := dao.Review.ExistByOrderItemID(item.ID)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if exist {
+		return nil, xerr.NewErrMsg("该商品已评价")
+	}
+
+	// 4️⃣ 事务：写入评价 + 增量更新评分汇总（商品 + SKU）
+	review := &model.Review{
+		OrderItemID:  item.ID,
+		OrderNum:     item.OrderNum,
+		UserID:       userID,
+		ProductID:    item.ProductID,
+		ProductSkuID: item.ProductSkuID,
+		SkuTitle:     item.Title,
+		Star:         req.Star,
+		Content:      req.Content,
+		Images:       encodeImages(req.Images),
+		HasImage:     len(req.Images) > 0,
+	}
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		if err := dao.Review.CreateReview(tx, review); err != nil {
+			return err
+		}
+		if err := dao.Review.IncrRatingStat(tx, item.ProductID, 0, req.Star); err != nil {
+			return err
+		}
+		return dao.Review.IncrRatingStat(tx, item.ProductID, item.ProductSkuID, req.Star)
+	})
+	if err != nil {
+		return nil, xerr.NewErrMsg("评价失败，请勿重复提交")
+	}
+
+	// 5️⃣ 评分变化，失效商品详情缓存
+	cache.Invalidate(ctx, cache.ProductDetailKey(item.ProductID))
+
+	return &vo.CreateReviewResp{ReviewID: review.ID}, nil
+}
+
+// 追评（只能追评一次）
+func (s *ReviewService) FollowUpReview(userID uint, req dto.FollowUpReviewReq) error {
+	review, err := dao.Review.GetByID(req.ReviewID)
+	if err != nil {
+		return xerr.NewErrMsg("评价不存在")
+	}
+	if review.UserID != userID {
+		return xerr.NewErrMsg("评价不属于当前用户")
+	}
+	if review.FollowUpTime != nil {
+		return xerr.NewErrMsg("已追评，不能重复追评")
+	}
+
+	rowsAffected, err := dao.Review.FollowUp(req.ReviewID, userID, req.Content, encodeImages(req.Images))
+	if err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	if rowsAffected == 0 {
+		return xerr.NewErrMsg("已追评，不能重复追评")
+	}
+	return nil
+}
+
+// 商品评价列表（分页 + 星级/有图筛选）
+func (s *ReviewService) ProductReviewList(req dto.ProductReviewListReq) (*vo.ReviewListResp, error) {
+	// 1️⃣ 分页参数默认值
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	// 2️⃣ 查询评价和评分汇总
+	reviews, total, err := dao.Review.GetProductReviews(req.ProductID, req.Star, req.HasImage, page, pageSize)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	ratings, err := dao.Review.GetRatingStats(req.ProductID)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.DB_ERROR)
+	}
+
+	// 3️⃣ 批量查询评价用户（昵称、头像）
+	users := make(map[uint]*model.User)
+	if len(reviews) > 0 {
+		userIDs := make([]uint, 0, len(reviews))
+		for _, review := range reviews {
+			userIDs = append(userIDs, review.UserID)
+		}
+		if users, err = dao.User.GetUsersByIDs(userIDs); err != nil {
+			return nil, xerr.NewErrCode(xerr.DB_ERROR)
+		}
+	}
+
+	// 4️⃣ 组装响应
+	list := make([]vo.ReviewVO, 0, len(reviews))
+	for _, review := range reviews {
+		item := vo.ReviewVO{
+			ReviewID:        review.ID,
+			SkuTitle:        review.SkuTitle,
+			Star:            review.Star,
+			Content:         review.Content,
+			Images:          decodeImages(review.Images),
+			CreatedAt:       review.CreatedAt,
+			FollowUpContent: review.FollowUpContent,
+			FollowUpImages:  decodeImages(review.FollowUpImages),
+			FollowUpTime:    review.FollowUpTime,
+			Reply:           review.Reply,
+			ReplyTime:       review.ReplyTime,
+		}
+		if user, ok := users[review.UserID]; ok {
+			item.NickName = user.NickName
+			item.Avatar = user.Avatar
+		}
+		list = append(list, item)
+	}
+
+	return &vo.ReviewListResp{
+		Rating:   toRatingVO(ratings[0]),
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// toRatingVO 评分汇总转 VO（平均分保留 1 位小数）
+func toRatingVO(stat *model.RatingStat) vo.RatingVO {
+	if stat == nil || stat.ReviewCount == 0 {
+		return vo.RatingVO{}
+	}
+	avg := float64(stat.StarSum) / float64(stat.ReviewCount)
+	return vo.RatingVO{
+		Average: math.Round(avg*10) / 10,
+		Count:   stat.ReviewCount,
+	}
+}
+
+// encodeImages 图片列表序列化为 JSON 存储
+func encodeImages(images []string) string {
+	if len(images) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(images)
+	return string(data)
+}
+
+// decodeImages 解析图片 JSON（确保返回 [] 而不是 null）
+func decodeImages(data string) []string {
+	images := make([]string, 0)
+	if data != "" {
+		json.Unmarshal([]byte(data), &images)
+	}
+	return images
+}
@@ -0,0 +1,45 @@
+package userService
+
+import (
+	"context"
+	"log"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/internal/pkg/orderevent"
+	"xiaomi-mall/internal/pkg/recommend"
+
+	"gorm.io/gorm"
+)
+
+// RegisterOrderEventHandlers 注册订单事件处理器（服务启动时调用一次）
+func RegisterOrderEventHandlers() {
+	// 销量：支付时增加，退款时扣减，和订单状态在同一事务内更新
+	orderevent.OnTx(orderevent.Paid, func(tx *gorm.DB, e *orderevent.Event) error {
+		return updateSalesNum(tx, e, 1)
+	})
+	orderevent.OnTx(orderevent.Refunded, func(tx *gorm.DB, e *orderevent.Event) error {
+		return updateSalesNum(tx, e, -1)
+	})
+
+	// 关联推荐：支付后增量累加共同购买次数（离线任务定期全量重建）
+	orderevent.On(orderevent.Paid, func(e *orderevent.Event) {
+		productIDs := recommend.DistinctProductIDs(e.Items)
+		if err := dao.Recommend.IncrCoPurchase(context.Background(), productIDs); err != nil {
+			log.Printf("❌ 更新关联推荐失败: order=%s err=%v", e.OrderNum, err)
+		}
+	})
+}
+
+// updateSalesNum 按商品汇总订单数量后更新销量（sign = 1 增加，-1 扣减）
+func updateSalesNum(tx *gorm.DB, e *orderevent.Event, sign int) error {
+	sales := make(map[uint]int)
+	for _, item := range e.Items {
+		sales[item.ProductID] += item.Num
+	}
+
+	for productID, num := range sales {
+		if err := dao.Product.IncrementSalesNum(tx, productID, sign*num); err != nil {
+			return err
+		}
+	}
+	return nil
+}
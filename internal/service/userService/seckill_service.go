@@ -6,18 +6,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"xiaomi-mall/internal/api/dto"
 	"xiaomi-mall/internal/api/vo"
 	"xiaomi-mall/internal/dao"
 	"xiaomi-mall/internal/model"
+	"xiaomi-mall/internal/pkg/orderevent"
+	"xiaomi-mall/internal/pkg/stockalert"
 	"xiaomi-mall/internal/pkg/types"
 	pkgBloom "xiaomi-mall/pkg/bloom"
+	"xiaomi-mall/pkg/constants"
 	"xiaomi-mall/pkg/idgen"
 	"xiaomi-mall/pkg/xerr"
 
-	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
@@ -250,7 +253,8 @@ func (s *SeckillService) CreateSeckillOrder(userID uint, req dto.CreateSeckillOr
 	//2.1 执行lua脚本
 	//生成订单号
 	orderNum := idgen.GenStringID()
-	result, err := dao.Seckill.CreateSeckillOrderAtomic(ctx, userID, req.SeckillProductID, orderNum)
+	expireTime := orderExpireTime(constants.ORDER_TYPE_SECKILL)
+	result, err := dao.Seckill.CreateSeckillOrderAtomic(ctx, userID, req.SeckillProductID, orderNum, expireTime)
 	if err != nil {
 		return nil, xerr.NewErrMsg("系统错误，请稍后重试")
 	}
@@ -264,12 +268,8 @@ func (s *SeckillService) CreateSeckillOrder(userID uint, req dto.CreateSeckillOr
 		return nil, xerr.NewErrMsg("秒杀活动未开始或已结束")
 	}
 
-	//2.2 将订单加入延迟队列（30分钟后超时）
-	expireTime := time.Now().Add(30 * time.Minute)
-	dao.Rdb.ZAdd(ctx, "order:delay:queue", &redis.Z{
-		Score:  float64(expireTime.Unix()),
-		Member: orderNum,
-	})
+	//2.2 将订单加入延迟队列（超时关单 + 待支付提醒）
+	scheduleOrderTimeout(orderNum, expireTime)
 
 	//2.3 返回秒杀成功（异步写入MySQL已在Lua脚本中投递到队列）
 	return &vo.CreateSeckillOrderResp{
@@ -280,10 +280,11 @@ func (s *SeckillService) CreateSeckillOrder(userID uint, req dto.CreateSeckillOr
 	}, nil
 }
 
-// ==================== 秒杀订单关闭（超时取消）====================
+// ==================== 秒杀订单关闭（超时 / 用户取消）====================
 
-// CloseSeckillOrder 关闭秒杀订单（回滚 Redis 库存和用户标记）
-func (s *SeckillService) CloseSeckillOrder(orderNum string) error {
+// CloseSeckillOrder 关闭秒杀订单（超时关单、用户取消共用，回滚库存和用户标记）
+// 活动仍在售时回补 Redis 秒杀库存，活动已删除或停售时归还到 SKU 库存；operator 为库存流水的操作人
+func (s *SeckillService) CloseSeckillOrder(orderNum, operator string) error {
 	ctx := context.Background()
 
 	// 1. 查询主订单信息
@@ -292,9 +293,9 @@ func (s *SeckillService) CloseSeckillOrder(orderNum string) error {
 		return err
 	}
 
-	// 2. 检查订单状态（只关闭未支付的订单）
-	if order.PayStatus != 0 {
-		return nil // 已支付，跳过
+	// 2. 检查订单状态（只关闭待支付的订单，已支付、已取消的跳过）
+	if order.OrderStatus != 0 || order.PayStatus != 0 {
+		return nil
 	}
 
 	// 3. 查询秒杀订单信息
@@ -303,35 +304,48 @@ func (s *SeckillService) CloseSeckillOrder(orderNum string) error {
 		return err
 	}
 
-	// 4. 事务：更新数据库订单状态
+	// 4. 事务：更新数据库订单状态 + 归还库存
+	var releasedSkuID uint
 	err := dao.DB.Transaction(func(tx *gorm.DB) error {
-		// 4.1 更新主订单状态
-		if err := tx.Model(&order).Updates(map[string]interface{}{
-			"order_status": 4, // 4=已取消
-			"cancel_time":  time.Now(),
-		}).Error; err != nil {
+		// 4.1 更新主订单状态（乐观锁，只更新待支付的订单；并发关单时只有一方能回滚库存）
+		rowsAffected, err := dao.Order.CloseOrder(tx, orderNum, order.Version)
+		if err != nil {
 			return err
 		}
+		if rowsAffected == 0 {
+			return xerr.NewErrMsg("订单状态已变更")
+		}
 
 		// 4.2 更新秒杀订单状态
 		if err := tx.Model(&seckillOrder).Update("status", 2).Error; err != nil { // 2=已取消
 			return err
 		}
 
-		return nil
+		// 4.3 归还库存（关键！活动在售时回补 Redis 秒杀库存，已删除时归还到 SKU）
+		releasedSkuID, err = dao.Seckill.ReturnSeckillOrderStock(ctx, tx, seckillOrder.SeckillProductID, orderNum, operator)
+		return err
 	})
 
 	if err != nil {
 		return err
 	}
 
-	// 5. 回滚 Redis 库存（关键！）
-	stockKey := fmt.Sprintf("seckill:stock:%d", seckillOrder.SeckillProductID)
-	dao.Rdb.Incr(ctx, stockKey)
+	// 5. 归还到 SKU 时同步 Redis 中的 SKU 库存
+	if releasedSkuID > 0 {
+		stockalert.Apply(ctx, map[uint]int{releasedSkuID: 1})
+	}
 
 	// 6. 删除用户购买标记（关键！）
 	userKey := fmt.Sprintf("seckill:user:%d:%d", seckillOrder.SeckillProductID, order.UserID)
 	dao.Rdb.Del(ctx, userKey)
 
+	// 7. 发布取消事件
+	items, err := dao.Order.GetOrderItems(dao.DB, orderNum)
+	if err != nil {
+		log.Printf("❌ 查询秒杀订单商品失败: order=%s err=%v", orderNum, err)
+		return nil
+	}
+	orderevent.Publish(orderevent.New(orderevent.Cancelled, &order, items))
+
 	return nil
 }
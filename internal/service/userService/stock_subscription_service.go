@@ -0,0 +1,37 @@
+package userService
+
+import (
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/dao"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type StockSubscriptionService struct{}
+
+var StockSubscription = new(StockSubscriptionService)
+
+// 订阅到货提醒（只有缺货的 SKU 可以订阅，到货后通知一次）
+func (s *StockSubscriptionService) Subscribe(userID uint, req dto.StockSubscriptionReq) error {
+	// 1️⃣ 校验 SKU
+	sku, err := dao.Product.GetSkuByID(req.SkuID)
+	if err != nil {
+		return xerr.NewErrCode(xerr.PRODUCT_SKU_NOT_FOUND)
+	}
+	if sku.Stock-sku.Reserved > 0 {
+		return xerr.NewErrMsg("商品有货，无需订阅到货提醒")
+	}
+
+	// 2️⃣ 写入订阅（重复订阅幂等）
+	if err := dao.StockAlert.Subscribe(userID, sku.ProductID, sku.ID); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	return nil
+}
+
+// 取消到货提醒
+func (s *StockSubscriptionService) Unsubscribe(userID uint, req dto.StockSubscriptionReq) error {
+	if err := dao.StockAlert.Unsubscribe(userID, req.SkuID); err != nil {
+		return xerr.NewErrCode(xerr.DB_ERROR)
+	}
+	return nil
+}
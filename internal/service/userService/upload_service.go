@@ -0,0 +1,176 @@
+package userService
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	_ "image/gif" // 注册 GIF 解码器
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"time"
+	"xiaomi-mall/config"
+	"xiaomi-mall/internal/api/dto"
+	"xiaomi-mall/internal/api/vo"
+	"xiaomi-mall/internal/pkg/storage"
+	"xiaomi-mall/pkg/xerr"
+)
+
+type UploadService struct{}
+
+var Upload = new(UploadService)
+
+const (
+	maxImageSide   = 8000             // 图片最大边长（防止解压炸弹）
+	thumbSide      = 300              // 缩略图最长边
+	thumbQuality   = 85               // 缩略图 JPEG 质量
+	presignExpires = 15 * time.Minute // 直传地址有效期
+)
+
+// 允许上传的图片类型及扩展名
+var imageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// 上传图片（校验类型/大小 → 按内容哈希去重 → 生成缩略图 → 写入存储）
+func (s *UploadService) UploadImage(file *multipart.FileHeader) (*vo.UploadImageResp, error) {
+	maxSize := config.AppConfig.OSS.MaxSize
+
+	// 1️⃣ 校验大小并读取内容
+	if file.Size > maxSize {
+		return nil, xerr.NewErrMsg("文件过大")
+	}
+	f, err := file.Open()
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.REUQEST_PARAM_ERROR)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.REUQEST_PARAM_ERROR)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, xerr.NewErrMsg("文件过大")
+	}
+
+	// 2️⃣ 按文件内容识别类型（不信任客户端的 Content-Type 和扩展名）
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExts[contentType]
+	if !ok {
+		return nil, xerr.NewErrMsg("只支持 JPG、PNG、GIF 图片")
+	}
+
+	// 3️⃣ 校验尺寸（只解析头部，不解码像素）
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, xerr.NewErrMsg("图片已损坏")
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide {
+		return nil, xerr.NewErrMsg("图片尺寸过大")
+	}
+
+	// 4️⃣ 按内容哈希生成 key，已存在则直接返回
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key, thumbKey := imageKeys(hash, ext)
+
+	resp := &vo.UploadImageResp{
+		Key:      key,
+		URL:      storage.Default.URL(key),
+		ThumbURL: storage.Default.URL(thumbKey),
+		Width:    cfg.Width,
+		Height:   cfg.Height,
+		Size:     int64(len(data)),
+	}
+
+	exists, err := storage.Default.Exists(ctx, key)
+	if err != nil {
+		log.Printf("⚠️  查询文件是否存在失败: %s, 错误: %v", key, err)
+	}
+	if exists {
+		resp.Duplicated = true
+		return resp, nil
+	}
+
+	// 5️⃣ 生成缩略图
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, xerr.NewErrMsg("图片已损坏")
+	}
+	thumb, err := storage.Thumbnail(img, thumbSide, thumbQuality)
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	// 6️⃣ 写入存储（先写缩略图，原图存在即代表缩略图也存在）
+	if err := storage.Default.Put(ctx, thumbKey, thumb, "image/jpeg"); err != nil {
+		log.Printf("❌ 上传缩略图失败: %s, 错误: %v", thumbKey, err)
+		return nil, xerr.NewErrMsg("上传失败，请稍后重试")
+	}
+	if err := storage.Default.Put(ctx, key, data, contentType); err != nil {
+		log.Printf("❌ 上传图片失败: %s, 错误: %v", key, err)
+		return nil, xerr.NewErrMsg("上传失败，请稍后重试")
+	}
+
+	return resp, nil
+}
+
+// 生成直传预签名地址（仅对象存储支持，直传不生成缩略图）
+func (s *UploadService) PresignUpload(req dto.PresignUploadReq) (*vo.PresignUploadResp, error) {
+	// 1️⃣ 存储后端是否支持直传
+	presigner, ok := storage.Default.(storage.Presigner)
+	if !ok {
+		return nil, xerr.NewErrMsg("当前存储不支持直传，请使用上传接口")
+	}
+	if req.Size > config.AppConfig.OSS.MaxSize {
+		return nil, xerr.NewErrMsg("文件过大")
+	}
+
+	// 2️⃣ 按客户端计算的哈希生成 key，已存在则无需上传
+	sum, _ := hex.DecodeString(req.SHA256)
+	key, _ := imageKeys(req.SHA256, imageExts[req.ContentType])
+	resp := &vo.PresignUploadResp{
+		Key: key,
+		URL: storage.Default.URL(key),
+	}
+
+	exists, err := storage.Default.Exists(ctx, key)
+	if err != nil {
+		log.Printf("⚠️  查询文件是否存在失败: %s, 错误: %v", key, err)
+	}
+	if exists {
+		resp.Exists = true
+		return resp, nil
+	}
+
+	// 3️⃣ 生成预签名地址（类型、大小、哈希都签入 URL，由存储端校验）
+	presigned, err := presigner.PresignPut(ctx, key, storage.PresignOptions{
+		ContentType:   req.ContentType,
+		ContentLength: req.Size,
+		SHA256:        sum,
+		Expires:       presignExpires,
+	})
+	if err != nil {
+		return nil, xerr.NewErrCode(xerr.SERVER_COMMON_ERROR)
+	}
+
+	expiresAt := time.Now().Add(presignExpires)
+	resp.UploadURL = presigned.URL
+	resp.Method = presigned.Method
+	resp.Headers = presigned.Headers
+	resp.ExpiresAt = &expiresAt
+	return resp, nil
+}
+
+// imageKeys 按内容哈希生成原图和缩略图的 key（前两位做目录分散）
+func imageKeys(hash, ext string) (key, thumbKey string) {
+	dir := "images/" + hash[:2] + "/"
+	return dir + hash + ext, dir + hash + "_thumb.jpg"
+}
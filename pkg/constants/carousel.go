@@ -0,0 +1,18 @@
+package constants
+
+const (
+	// CarouselSlot 运营位
+	CAROUSEL_SLOT_HOME         = "home_carousel" // 首页轮播
+	CAROUSEL_SLOT_CATEGORY_TOP = "category_top"  // 分类精选
+	CAROUSEL_SLOT_NEW_ARRIVAL  = "new_arrival"   // 新品推荐
+
+	// CarouselTargetType 点击跳转类型
+	CAROUSEL_TARGET_PRODUCT  = "product"  // 商品详情
+	CAROUSEL_TARGET_CATEGORY = "category" // 分类页
+	CAROUSEL_TARGET_URL      = "url"      // 外部链接 / 活动页
+
+	// CarouselStatus 运营位状态
+	CAROUSEL_STATUS_DISABLED = 0 // 停用
+	CAROUSEL_STATUS_ENABLED  = 1 // 启用
+	CAROUSEL_STATUS_EXPIRED  = 2 // 已过期（定时任务自动标记）
+)
@@ -0,0 +1,14 @@
+package constants
+
+// 批量导入任务类型
+const (
+	IMPORT_TYPE_PRODUCT = "product"
+)
+
+// 批量导入任务状态
+const (
+	IMPORT_STATUS_PENDING = 0 // 排队中
+	IMPORT_STATUS_RUNNING = 1 // 执行中
+	IMPORT_STATUS_DONE    = 2 // 已完成（可能有部分行失败）
+	IMPORT_STATUS_FAILED  = 3 // 失败（整个文件无法处理）
+)
@@ -0,0 +1,40 @@
+package constants
+
+const (
+	// StockReason 库存流水变更原因（InventoryMovement.Reason）
+	STOCK_REASON_ORDER           = "order"           // 下单扣减
+	STOCK_REASON_CANCEL          = "cancel"          // 用户取消订单回补
+	STOCK_REASON_TIMEOUT         = "timeout"         // 支付超时关单回补
+	STOCK_REASON_REFUND          = "refund"          // 退款回补
+	STOCK_REASON_ADMIN_ADJUST    = "admin_adjust"    // 管理员调整
+	STOCK_REASON_SECKILL_RESERVE = "seckill_reserve" // 秒杀活动预留
+	STOCK_REASON_SECKILL_RELEASE = "seckill_release" // 秒杀活动删除，归还未售出的预留库存（含删除后关闭、退款的秒杀订单）
+	STOCK_REASON_INITIAL         = "initial"         // 新建 SKU 的初始库存
+	STOCK_REASON_IMPORT          = "import"          // 批量导入覆盖库存
+
+	// STOCK_OPERATOR_SYSTEM 系统任务（超时关单等）的操作人
+	STOCK_OPERATOR_SYSTEM = "system"
+)
+
+const (
+	// ReservationStatus 库存预占状态（StockReservation.Status）
+	RESERVATION_STATUS_ACTIVE    = 0 // 预占中
+	RESERVATION_STATUS_CONVERTED = 1 // 已支付，转为实际扣减
+	RESERVATION_STATUS_RELEASED  = 2 // 已释放（取消 / 超时 / 订单不存在）
+)
+
+const (
+	// StockMode SKU 下单预占库存的方式（ProductSku.StockMode）
+	STOCK_MODE_OPTIMISTIC  = 0 // 乐观锁：校验读取时的版本号，并发更新时需要重试
+	STOCK_MODE_CONDITIONAL = 1 // 条件扣减：只校验可售库存，热点 SKU 不会因并发更新失败
+)
+
+const (
+	// StockAlertStatus 低库存告警状态（StockAlert.Status）
+	STOCK_ALERT_STATUS_OPEN     = 0 // 告警中
+	STOCK_ALERT_STATUS_RESOLVED = 1 // 已恢复
+
+	// StockSubscriptionStatus 到货提醒订阅状态（StockSubscription.Status）
+	STOCK_SUBSCRIPTION_WAITING  = 0 // 等待到货
+	STOCK_SUBSCRIPTION_NOTIFIED = 1 // 已通知
+)
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// NotificationType 站内通知类型
+	NOTIFICATION_PRICE_DROP    = "price_drop"    // 收藏商品降价
+	NOTIFICATION_BACK_IN_STOCK = "back_in_stock" // 订阅的 SKU 到货
+	NOTIFICATION_PAY_REMINDER  = "pay_reminder"  // 订单即将超时，提醒支付
+)
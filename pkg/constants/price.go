@@ -0,0 +1,11 @@
+package constants
+
+const (
+	// PriceRuleStatus 价格规则状态
+	PRICE_RULE_STATUS_CANCELLED = 0 // 已取消
+	PRICE_RULE_STATUS_ACTIVE    = 1 // 有效
+
+	// PriceSource 售价变更来源（PriceHistory.Source）
+	PRICE_SOURCE_BASE = "base" // SKU 原价调整（新建、改价、批量导入）
+	PRICE_SOURCE_RULE = "rule" // 价格规则生效 / 结束 / 取消
+)
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GCRALimiter GCRA 限流器（通用信元速率算法）
+// 每个 key 只保存一个"理论到达时间"（TAT），请求按 window/limit 的间隔均匀放行，最多提前 burst 个间隔
+type GCRALimiter struct {
+	rdb    *redis.Client
+	limit  int           // 每个窗口允许的请求数
+	window time.Duration // 时间窗口
+	burst  int           // 突发容量
+}
+
+// gcraScript
+// KEYS[1] 限流 key；ARGV[1] 放行间隔（微秒）；ARGV[2] 突发容量；ARGV[3] 本次请求数；ARGV[4] 1 表示只判断不计数
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local tolerance = interval * burst
+
+-- 1. 计算放行后的理论到达时间
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+local new_tat = tat + interval * n
+
+-- 2. 超出容忍范围：拒绝，等待到 new_tat - tolerance
+local allow_at = new_tat - tolerance
+if allow_at > now then
+	local retry = math.ceil(allow_at - now)
+	return {0, math.max(0, math.floor((tolerance - (tat - now)) / interval)), retry}
+end
+
+-- 3. 放行并保存 TAT（TAT 过去后 key 自然过期，等同满额）
+if ARGV[4] == '1' then
+	return {1, math.max(0, math.floor((tolerance - (new_tat - now)) / interval)), 0}
+end
+redis.call('SET', KEYS[1], string.format('%.17g', new_tat), 'PX', math.ceil((new_tat - now) / 1000) + 1)
+return {1, math.max(0, math.floor((tolerance - (new_tat - now)) / interval)), 0}
+`)
+
+// NewGCRALimiter 创建 GCRA 限流器
+// limit / window: 平均速率（如 1 秒 100 次）
+// burst: 允许的突发请求数，<= 0 时等于 limit
+func NewGCRALimiter(rdb *redis.Client, limit int, window time.Duration, burst int) *GCRALimiter {
+	if burst <= 0 {
+		burst = limit
+	}
+	return &GCRALimiter{
+		rdb:    rdb,
+		limit:  limit,
+		window: window,
+		burst:  burst,
+	}
+}
+
+// Allow 检查是否允许请求
+func (l *GCRALimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN 检查是否允许 N 次请求
+func (l *GCRALimiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	interval := float64(micros(l.window)) / float64(l.limit)
+	return runScript(ctx, l.rdb, gcraScript, key, l.burst, interval, l.burst, n, 0)
+}
+
+// Peek 判断 N 次请求是否会被放行（不保存 TAT）
+func (l *GCRALimiter) Peek(ctx context.Context, key string, n int) (*Result, error) {
+	interval := float64(micros(l.window)) / float64(l.limit)
+	return runScript(ctx, l.rdb, gcraScript, key, l.burst, interval, l.burst, n, 1)
+}
+
+// Reset 重置限流状态
+func (l *GCRALimiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, key).Err()
+}
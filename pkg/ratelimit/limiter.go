@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 限流算法
+const (
+	AlgorithmSlidingWindow = "sliding_window" // 滑动窗口（按上一窗口计数加权估算，内存占用固定）
+	AlgorithmTokenBucket   = "token_bucket"   // 令牌桶（允许突发 Burst 个请求，之后按速率补充）
+	AlgorithmGCRA          = "gcra"           // GCRA（通用信元速率算法，请求均匀放行，允许 Burst 个突发）
+)
+
+// Limiter 限流器（所有实现都是单个 Lua 脚本完成判断和计数，多实例并发下不会超限）
+type Limiter interface {
+	// Allow 请求 1 个配额
+	Allow(ctx context.Context, key string) (*Result, error)
+	// AllowN 一次请求 n 个配额（要么全部放行，要么全部拒绝）
+	AllowN(ctx context.Context, key string, n int) (*Result, error)
+	// Peek 判断 n 个配额是否会被放行，但不计数（多条规则同时生效时先检查再扣减）
+	Peek(ctx context.Context, key string, n int) (*Result, error)
+	// Reset 清空 key 的限流状态（用于测试或手动解封）
+	Reset(ctx context.Context, key string) error
+}
+
+// Result 限流判断结果
+type Result struct {
+	Allowed    bool          // 是否放行
+	Limit      int           // 配额上限（滑动窗口为窗口内次数，令牌桶 / GCRA 为突发容量）
+	Remaining  int           // 剩余配额
+	RetryAfter time.Duration // 被拒绝时建议的重试等待时间（放行时为 0）
+}
+
+// Config 限流器参数
+type Config struct {
+	Algorithm string        // 限流算法，为空时使用滑动窗口
+	Limit     int           // 每个窗口允许的请求数
+	Window    time.Duration // 时间窗口
+	Burst     int           // 令牌桶 / GCRA 的突发容量，0 表示等于 Limit
+}
+
+// New 按配置创建限流器
+func New(rdb *redis.Client, cfg Config) (Limiter, error) {
+	if cfg.Limit <= 0 || cfg.Window <= 0 {
+		return nil, fmt.Errorf("限流配置错误：limit 和 window 必须大于 0")
+	}
+	switch cfg.Algorithm {
+	case "", AlgorithmSlidingWindow:
+		return NewSlidingWindowLimiter(rdb, cfg.Limit, cfg.Window), nil
+	case AlgorithmTokenBucket:
+		return NewTokenBucketLimiter(rdb, cfg.Limit, cfg.Window, cfg.Burst), nil
+	case AlgorithmGCRA:
+		return NewGCRALimiter(rdb, cfg.Limit, cfg.Window, cfg.Burst), nil
+	default:
+		return nil, fmt.Errorf("不支持的限流算法：%s", cfg.Algorithm)
+	}
+}
+
+// runScript 执行限流脚本，脚本统一返回 {是否放行, 剩余配额, 重试等待微秒}
+// 脚本的最后一个参数为 dry（1 表示只判断不计数）
+func runScript(ctx context.Context, rdb *redis.Client, script *redis.Script, key string, limit int, args ...interface{}) (*Result, error) {
+	values, err := script.Run(ctx, rdb, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("限流脚本返回值错误：%v", values)
+	}
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
+
+// micros 时长转微秒（脚本内统一使用 Redis TIME 的微秒时间，避免各实例时钟不一致；
+// 微秒时间戳超过 14 位有效数字，脚本写入时用 %.17g 格式化，避免 Lua 默认的数字转字符串丢精度）
+func micros(d time.Duration) int64 {
+	return d.Microseconds()
+}
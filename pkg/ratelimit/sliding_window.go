@@ -2,27 +2,77 @@ package ratelimit
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
 // SlidingWindowLimiter 滑动窗口限流器
+// 每个 key 只保存当前窗口和上一窗口的计数（HASH），按上一窗口剩余时间比例加权估算滑动窗口内的请求数
 type SlidingWindowLimiter struct {
 	rdb    *redis.Client
-	ctx    context.Context
 	limit  int           // 限流次数
 	window time.Duration // 时间窗口
 }
 
+// slidingWindowScript
+// KEYS[1] 限流 key；ARGV[1] 窗口（微秒）；ARGV[2] 限流次数；ARGV[3] 本次请求数；ARGV[4] 1 表示只判断不计数
+var slidingWindowScript = redis.NewScript(`
+redis.replicate_commands()
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+-- 1. 读取计数，窗口滚动时把当前窗口计数移到上一窗口
+local data = redis.call('HMGET', KEYS[1], 'start', 'cur', 'prev')
+local cur_start = now - (now % window)
+local start = tonumber(data[1]) or 0
+local cur = tonumber(data[2]) or 0
+local prev = tonumber(data[3]) or 0
+if start ~= cur_start then
+	if start == cur_start - window then
+		prev = cur
+	else
+		prev = 0
+	end
+	cur = 0
+end
+
+-- 2. 估算滑动窗口内的请求数
+local elapsed = now - cur_start
+local estimated = prev * (window - elapsed) / window + cur
+
+-- 3. 超限：计算需要等待多久（当前窗口已满时等到下一窗口）
+if estimated + n > limit then
+	local retry
+	if cur + n <= limit and prev > 0 then
+		retry = math.ceil(window * (1 - (limit - cur - n) / prev)) - elapsed
+	else
+		retry = window - elapsed
+	end
+	if retry < 1 then
+		retry = 1
+	end
+	return {0, math.max(0, math.floor(limit - estimated)), retry}
+end
+
+-- 4. 放行并计数
+if ARGV[4] == '1' then
+	return {1, math.max(0, math.floor(limit - estimated - n)), 0}
+end
+redis.call('HSET', KEYS[1], 'start', string.format('%.17g', cur_start), 'cur', cur + n, 'prev', prev)
+redis.call('PEXPIRE', KEYS[1], math.ceil(window * 2 / 1000))
+return {1, math.max(0, math.floor(limit - estimated - n)), 0}
+`)
+
 // NewSlidingWindowLimiter 创建滑动窗口限流器
 // limit: 时间窗口内允许的最大请求次数
 // window: 时间窗口大小（如 1 秒）
 func NewSlidingWindowLimiter(rdb *redis.Client, limit int, window time.Duration) *SlidingWindowLimiter {
 	return &SlidingWindowLimiter{
 		rdb:    rdb,
-		ctx:    context.Background(),
 		limit:  limit,
 		window: window,
 	}
@@ -30,109 +80,21 @@ func NewSlidingWindowLimiter(rdb *redis.Client, limit int, window time.Duration)
 
 // Allow 检查是否允许请求
 // key: 限流维度的唯一标识（如 "user:123", "ip:192.168.1.1"）
-// memberID: 请求的唯一标识（使用雪花算法生成）
-// 返回：是否允许，当前请求数
-func (l *SlidingWindowLimiter) Allow(key string, memberID string) (bool, int, error) {
-	now := time.Now().UnixMilli() // 当前时间戳（毫秒）
-	windowStart := now - l.window.Milliseconds()
-
-	// 使用 Redis Pipeline 提高性能（一次网络 IO）
-	pipe := l.rdb.Pipeline()
-
-	// 1. 删除窗口外的过期数据
-	pipe.ZRemRangeByScore(l.ctx, key, "0", fmt.Sprintf("%d", windowStart))
-
-	// 2. 统计当前窗口内的请求数
-	countCmd := pipe.ZCard(l.ctx, key)
-
-	// 3. 执行 Pipeline
-	_, err := pipe.Exec(l.ctx)
-	if err != nil {
-		return false, 0, err
-	}
-
-	// 获取当前请求数
-	count := int(countCmd.Val())
-
-	// 4. 判断是否超限
-	if count >= l.limit {
-		return false, count, nil // 拒绝请求
-	}
-
-	// 5. 添加当前请求到 ZSet
-	err = l.rdb.ZAdd(l.ctx, key, &redis.Z{
-		Score:  float64(now),
-		Member: memberID,
-	}).Err()
-	if err != nil {
-		return false, count, err
-	}
-
-	// 6. 设置 key 过期时间（防止内存泄漏）
-	// 过期时间设为窗口的 2 倍，确保数据清理
-	l.rdb.Expire(l.ctx, key, l.window*2)
-
-	return true, count + 1, nil // 允许请求
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
 }
 
 // AllowN 检查是否允许 N 次请求（批量操作）
-func (l *SlidingWindowLimiter) AllowN(key string, memberIDs []string) (bool, int, error) {
-	now := time.Now().UnixMilli()
-	windowStart := now - l.window.Milliseconds()
-
-	pipe := l.rdb.Pipeline()
-
-	// 1. 删除过期数据
-	pipe.ZRemRangeByScore(l.ctx, key, "0", fmt.Sprintf("%d", windowStart))
-
-	// 2. 统计当前请求数
-	countCmd := pipe.ZCard(l.ctx, key)
-
-	_, err := pipe.Exec(l.ctx)
-	if err != nil {
-		return false, 0, err
-	}
-
-	count := int(countCmd.Val())
-
-	// 3. 判断是否超限（加上即将添加的 N 个请求）
-	if count+len(memberIDs) > l.limit {
-		return false, count, nil
-	}
-
-	// 4. 批量添加请求
-	members := make([]*redis.Z, len(memberIDs))
-	for i, id := range memberIDs {
-		members[i] = &redis.Z{
-			Score:  float64(now),
-			Member: id,
-		}
-	}
-	err = l.rdb.ZAdd(l.ctx, key, members...).Err()
-	if err != nil {
-		return false, count, err
-	}
-
-	// 5. 设置过期时间
-	l.rdb.Expire(l.ctx, key, l.window*2)
-
-	return true, count + len(memberIDs), nil
+func (l *SlidingWindowLimiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	return runScript(ctx, l.rdb, slidingWindowScript, key, l.limit, micros(l.window), l.limit, n, 0)
 }
 
-// Reset 重置限流计数（用于测试或手动清空）
-func (l *SlidingWindowLimiter) Reset(key string) error {
-	return l.rdb.Del(l.ctx, key).Err()
+// Peek 判断 N 次请求是否会被放行（不计数）
+func (l *SlidingWindowLimiter) Peek(ctx context.Context, key string, n int) (*Result, error) {
+	return runScript(ctx, l.rdb, slidingWindowScript, key, l.limit, micros(l.window), l.limit, n, 1)
 }
 
-// GetCount 获取当前窗口内的请求数（用于监控）
-func (l *SlidingWindowLimiter) GetCount(key string) (int, error) {
-	now := time.Now().UnixMilli()
-	windowStart := now - l.window.Milliseconds()
-
-	// 先清理过期数据
-	l.rdb.ZRemRangeByScore(l.ctx, key, "0", fmt.Sprintf("%d", windowStart))
-
-	// 统计请求数
-	count, err := l.rdb.ZCard(l.ctx, key).Result()
-	return int(count), err
+// Reset 重置限流计数（用于测试或手动清空）
+func (l *SlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, key).Err()
 }
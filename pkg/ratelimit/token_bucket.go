@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// TokenBucketLimiter 令牌桶限流器
+// 桶容量为 burst，每个窗口补充 limit 个令牌；桶满时允许一次性突发 burst 个请求
+type TokenBucketLimiter struct {
+	rdb    *redis.Client
+	limit  int           // 每个窗口补充的令牌数
+	window time.Duration // 时间窗口
+	burst  int           // 桶容量
+}
+
+// tokenBucketScript
+// KEYS[1] 限流 key；ARGV[1] 每微秒补充的令牌数；ARGV[2] 桶容量；ARGV[3] 本次请求数；ARGV[4] 1 表示只判断不计数
+var tokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+-- 1. 按距上次的时间补充令牌（新 key 视为满桶）
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if not tokens or not ts then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+-- 2. 令牌不足：等待补足 n 个令牌的时间（n 超过桶容量时永远无法满足，按补满整桶计算）
+if tokens < n then
+	local retry = math.ceil((math.min(n, burst) - tokens) / rate)
+	if retry < 1 then
+		retry = 1
+	end
+	return {0, math.floor(tokens), retry}
+end
+
+-- 3. 放行并扣减令牌（key 在补满整桶后过期，过期等同满桶）
+tokens = tokens - n
+if ARGV[4] == '1' then
+	return {1, math.floor(tokens), 0}
+end
+redis.call('HSET', KEYS[1], 'tokens', string.format('%.17g', tokens), 'ts', string.format('%.17g', now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate / 1000) + 1000)
+return {1, math.floor(tokens), 0}
+`)
+
+// NewTokenBucketLimiter 创建令牌桶限流器
+// limit / window: 令牌补充速率（如 1 秒 100 个）
+// burst: 桶容量（允许的突发请求数），<= 0 时等于 limit
+func NewTokenBucketLimiter(rdb *redis.Client, limit int, window time.Duration, burst int) *TokenBucketLimiter {
+	if burst <= 0 {
+		burst = limit
+	}
+	return &TokenBucketLimiter{
+		rdb:    rdb,
+		limit:  limit,
+		window: window,
+		burst:  burst,
+	}
+}
+
+// Allow 检查是否允许请求
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN 检查是否允许 N 次请求（一次消耗 N 个令牌）
+func (l *TokenBucketLimiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	rate := float64(l.limit) / float64(micros(l.window))
+	return runScript(ctx, l.rdb, tokenBucketScript, key, l.burst, rate, l.burst, n, 0)
+}
+
+// Peek 判断 N 个令牌是否足够（不扣减）
+func (l *TokenBucketLimiter) Peek(ctx context.Context, key string, n int) (*Result, error) {
+	rate := float64(l.limit) / float64(micros(l.window))
+	return runScript(ctx, l.rdb, tokenBucketScript, key, l.burst, rate, l.burst, n, 1)
+}
+
+// Reset 重置令牌桶（恢复为满桶）
+func (l *TokenBucketLimiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, key).Err()
+}
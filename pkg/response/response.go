@@ -36,3 +36,16 @@ func Error(c *gin.Context, errCode uint32, errMsg string) {
 		Data: nil,
 	})
 }
+
+// ErrorWithStatus 失败响应（使用指定的 HTTP 状态码，如限流返回 429）并中止后续处理
+func ErrorWithStatus(c *gin.Context, httpStatus int, errCode uint32, errMsg string) {
+	if errMsg == "" {
+		errMsg = xerr.MapErrMsg(errCode)
+	}
+
+	c.AbortWithStatusJSON(httpStatus, Response{
+		Code: int(errCode),
+		Msg:  errMsg,
+		Data: nil,
+	})
+}
@@ -33,6 +33,7 @@ const (
 	PRODUCT_SKU_MISMATCH  = 300004 // SKU不属于该商品
 	PRODUCT_STOCK_INVALID = 300005 // 库存值无效
 	PRODUCT_NOT_FOUND     = 300006 // 商品不存在
+	PRODUCT_OFF_SALE      = 300007 // 商品已下架
 
 	// 限流模块错误码 (400xxx)
 	RATE_LIMIT_ERROR = 400001 // 请求过于频繁
@@ -32,6 +32,7 @@ func init() {
 	message[PRODUCT_SKU_MISMATCH] = "SKU不属于该商品"
 	message[PRODUCT_STOCK_INVALID] = "库存值无效，必须大于等于0"
 	message[PRODUCT_NOT_FOUND] = "商品不存在"
+	message[PRODUCT_OFF_SALE] = "商品已下架"
 
 	// --- 限流模块错误 400xxx ---
 	message[RATE_LIMIT_ERROR] = "请求过于频繁，请稍后重试"